@@ -0,0 +1,776 @@
+// Command grim-reaper cordons, drains, and deletes aging Kubernetes nodes
+// so the cluster keeps cycling onto fresh capacity.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+
+	compute "google.golang.org/api/compute/v1"
+
+	azcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gopkg.in/yaml.v3"
+
+	"github.com/BrianKopp/grim-reaper/internal/cloud/aws"
+	"github.com/BrianKopp/grim-reaper/internal/cloud/azure"
+	"github.com/BrianKopp/grim-reaper/internal/cloud/gce"
+	"github.com/BrianKopp/grim-reaper/internal/config"
+	"github.com/BrianKopp/grim-reaper/internal/dashboards"
+	"github.com/BrianKopp/grim-reaper/internal/fakecluster"
+	"github.com/BrianKopp/grim-reaper/internal/leaderelection"
+	"github.com/BrianKopp/grim-reaper/internal/ledger"
+	"github.com/BrianKopp/grim-reaper/internal/metrics"
+	"github.com/BrianKopp/grim-reaper/internal/notify"
+	"github.com/BrianKopp/grim-reaper/internal/preflight"
+	"github.com/BrianKopp/grim-reaper/internal/reaper"
+	"github.com/BrianKopp/grim-reaper/internal/schedule"
+)
+
+// version is grim-reaper's build version, overridable at build time with
+// -ldflags "-X main.version=...".
+var version = "dev"
+
+func main() {
+	subcommand, subcommandArg := shiftSubcommand()
+
+	if subcommand == "version" {
+		fmt.Println(version)
+		return
+	}
+
+	cfg := config.New()
+
+	if len(cfg.FeatureGates) > 0 {
+		log.Printf("feature gates: %s", formatFeatureGates(cfg.FeatureGates))
+	}
+
+	if cfg.GenerateDashboardsDir != "" {
+		if err := writeDashboards(cfg.GenerateDashboardsDir); err != nil {
+			log.Fatalf("generating dashboards: %v", err)
+		}
+		return
+	}
+
+	if cfg.PrintConfig {
+		if err := printConfig(os.Stdout, cfg); err != nil {
+			log.Fatalf("printing config: %v", err)
+		}
+		return
+	}
+
+	clientset, err := newClientsetOrFake(cfg)
+	if err != nil {
+		log.Fatalf("building kubernetes client: %v", err)
+	}
+	evictionAPIVersion := "v1beta1"
+	if cfg.FakeCluster == "" {
+		if err := preflight.Check(clientset, cfg); err != nil {
+			log.Fatalf("preflight RBAC check: %v", err)
+		}
+		evictionAPIVersion, err = reaper.CheckServerCompatibility(clientset, cfg.LeaderElection)
+		if err != nil {
+			log.Fatalf("checking server compatibility: %v", err)
+		}
+		log.Printf("using policy/%s for pod evictions", evictionAPIVersion)
+
+		cfg.SelfNodeName, err = reaper.ResolveSelfNodeName(clientset, cfg.SelfNodeName, cfg.PodName, cfg.PodNamespace)
+		if err != nil {
+			log.Fatalf("resolving self node name: %v", err)
+		}
+	}
+
+	backoff := reaper.NewBackoffPolicy(cfg.EvictionBackoffPolicy, cfg.EvictionBackoffBase, cfg.EvictionBackoffMax)
+	var pods reaper.PodEvictor
+	if cfg.ExtendedGracePeriod {
+		pods = reaper.NewPodEvictorWithExtendedGracePeriod(clientset, backoff, cfg.EvictionMaxRetries, cfg.MaxGracePeriodSeconds, evictionAPIVersion)
+	} else {
+		pods = reaper.NewPodEvictorWithBackoff(clientset, backoff, cfg.EvictionMaxRetries, evictionAPIVersion)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if cfg.PodInformerCache {
+		cachedPods, err := reaper.NewInformerPodEvictor(ctx, clientset, pods, cfg.PodInformerSyncTimeout)
+		if err != nil {
+			log.Fatalf("starting pod informer cache: %v", err)
+		}
+		pods = cachedPods
+	}
+
+	g := reaper.New(reaper.NewNodeClient(clientset), pods, cfg)
+	g.Namespaces = reaper.NewNamespaceClient(clientset)
+	g.PVCs = reaper.NewPVCClient(clientset)
+	g.Events = reaper.NewEventClient(clientset)
+	g.PDBs = reaper.NewPDBClient(clientset)
+	g.Notifier = buildNotifierRouter(cfg)
+	g.Recorder = buildEventRecorder(clientset)
+	if err := wireCloudProvider(g, cfg); err != nil {
+		log.Fatalf("configuring cloud provider %q: %v", cfg.CloudProvider, err)
+	}
+
+	if cfg.AsyncTermination {
+		g.AsyncTerminator = reaper.NewAsyncTerminator(ctx, g.Cloud, backoff, cfg.TerminationMaxRetries, cfg.CloudTerminationTimeout)
+	}
+	if cfg.PassoverLedgerEnabled {
+		g.Ledger = ledger.NewConfigMapLedger(clientset, cfg.LeaseNamespace, "grim-reaper-passover-ledger", cfg.PassoverLedgerTTL)
+	}
+	g.PassoverEscalationThreshold = cfg.PassoverEscalationThreshold
+	if cfg.ExternalDisruptionLedgerEnabled {
+		g.DisruptionReader = ledger.NewConfigMapDisruptionReader(clientset, cfg.LeaseNamespace, cfg.ExternalDisruptionLedgerName)
+	}
+	if cfg.PrometheusAlertGateURL != "" {
+		g.Alerts = reaper.NewPrometheusAlertGate(cfg.PrometheusAlertGateURL, cfg.PrometheusAlertGateQuery)
+	}
+	if cfg.DrainBackend == "kubectl" {
+		drainTimeout := cfg.EvictionBackoffMax
+		if cfg.ExtendedGracePeriod {
+			// The kubectl drain helper waits out each pod's own grace
+			// period before moving on, so its overall timeout needs to
+			// cover the worst case, not just the usual backoff window.
+			drainTimeout = time.Duration(cfg.MaxGracePeriodSeconds) * time.Second
+		}
+		g.Drainer = reaper.NewKubectlDrainer(clientset, drainTimeout, evictionAPIVersion)
+	}
+
+	switch subcommand {
+	case "plan":
+		if err := runPlanCommand(ctx, g, cfg); err != nil {
+			log.Fatalf("plan: %v", err)
+		}
+		return
+	case "uncordon":
+		if err := runUncordonCommand(ctx, g, subcommandArg); err != nil {
+			log.Fatalf("uncordon: %v", err)
+		}
+		return
+	case "status":
+		if err := runStatusCommand(ctx, g, cfg); err != nil {
+			log.Fatalf("status: %v", err)
+		}
+		return
+	case "support-bundle":
+		if err := runSupportBundleCommand(ctx, g, cfg, subcommandArg); err != nil {
+			log.Fatalf("support-bundle: %v", err)
+		}
+		return
+	}
+
+	window, err := buildMaintenanceWindow(cfg)
+	if err != nil {
+		log.Fatalf("parsing --schedule: %v", err)
+	}
+
+	if cfg.ConfigzAddr != "" {
+		go serveConfigz(cfg.ConfigzAddr, cfg)
+	}
+
+	var elector *leaderelection.Elector
+	if cfg.LeaderElection && cfg.FakeCluster == "" {
+		elector, err = leaderelection.New(clientset, cfg.LeaseNamespace, cfg.LeaseName, cfg.LockType)
+		if err != nil {
+			log.Fatalf("setting up leader election: %v", err)
+		}
+		go elector.Run(ctx)
+	}
+
+	if cfg.StatusAddr != "" {
+		go serveStatus(ctx, cfg.StatusAddr, g, elector)
+	}
+
+	var stopping atomic.Bool
+	g.StopRequested = stopping.Load
+	go watchForShutdownSignals(ctx, cancel, g, cfg, &stopping)
+
+	if cfg.WarmupScanEnabled {
+		runWarmupScan(ctx, g)
+	}
+
+	runCycleIfInWindow(ctx, g, cfg, window, clientset, elector)
+	if cfg.Mode != "daemon" {
+		return
+	}
+
+	go watchForPlanSignal(ctx, g)
+
+	for {
+		time.Sleep(cfg.RunInterval + time.Duration(rand.Int63n(int64(cfg.RunIntervalJitter)+1)))
+		runCycleIfInWindow(ctx, g, cfg, window, clientset, elector)
+	}
+}
+
+// buildMaintenanceWindow parses cfg.Schedule, if set, into a
+// schedule.Window. It returns nil, nil when no schedule is configured, in
+// which case every cycle is allowed to run.
+func buildMaintenanceWindow(cfg *config.Config) (*schedule.Window, error) {
+	if cfg.Schedule == "" {
+		return nil, nil
+	}
+	return schedule.NewWindow(cfg.Schedule, cfg.MaintenanceWindowDuration)
+}
+
+// runCycleIfInWindow skips the cycle entirely when a maintenance window is
+// configured and the current time falls outside it, or when leader election
+// is enabled and this replica isn't currently holding the lease.
+func runCycleIfInWindow(ctx context.Context, g *reaper.GrimReaper, cfg *config.Config, window *schedule.Window, clientset kubernetes.Interface, elector *leaderelection.Elector) {
+	if window != nil && !window.InWindow(time.Now()) {
+		log.Println("outside the configured maintenance window; skipping this cycle")
+		return
+	}
+	if elector != nil && !elector.IsLeader() {
+		log.Println("not currently leading; skipping this cycle")
+		return
+	}
+	runCycle(ctx, g, cfg, clientset)
+}
+
+// runCycle executes a single reap (or observe) cycle, pushes metrics if
+// configured, and logs a one-line summary.
+func runCycle(ctx context.Context, g *reaper.GrimReaper, cfg *config.Config, clientset kubernetes.Interface) {
+	start := time.Now()
+
+	if cfg.FakeCluster == "" {
+		if err := preflight.Check(clientset, cfg); err != nil {
+			log.Printf("skipping cycle, preflight RBAC check failed: %v", err)
+			return
+		}
+	}
+
+	var err error
+	if cfg.ObserverMode {
+		err = g.ObserveRun(ctx)
+	} else {
+		err = g.Run(ctx)
+		if reportErr := reaper.WriteReport(g.LastReport, cfg.ReportFile); reportErr != nil {
+			log.Printf("writing run report: %v", reportErr)
+		}
+	}
+	if err != nil {
+		log.Printf("run failed: %v", err)
+	}
+
+	if cfg.PushgatewayURL != "" {
+		if pushErr := metrics.PushToGateway(cfg.PushgatewayURL, cfg.PushgatewayJobName); pushErr != nil {
+			log.Printf("pushing metrics to pushgateway: %v", pushErr)
+		}
+	}
+
+	log.Printf("cycle complete in %s, observerMode=%t, err=%v", time.Since(start), cfg.ObserverMode, err)
+}
+
+// runWarmupScan runs a read-only inventory scan and logs a summary, so a
+// freshly deployed grim-reaper's first action is never a surprise.
+func runWarmupScan(ctx context.Context, g *reaper.GrimReaper) {
+	report, err := g.Warmup(ctx)
+	if err != nil {
+		log.Printf("startup warm-up scan: %v", err)
+		return
+	}
+	log.Printf("startup warm-up scan: %d node(s), %d eligible, %d marked for destruction (%d stale), %d orphaned cordon(s)",
+		report.TotalNodes, report.EligibleNodes, len(report.MarkedForDestruction), len(report.StaleMarkers), len(report.OrphanedCordons))
+	if len(report.OrphanedCordons) > 0 {
+		log.Printf("orphaned cordons (cordoned by something other than grim-reaper): %s", strings.Join(report.OrphanedCordons, ", "))
+	}
+}
+
+// triggerPlanRecompute runs the observer pipeline once, immediately, so an
+// operator can see the effect of a label/annotation change without waiting
+// for the next scheduled cycle. Like ObserveRun, it never mutates the
+// cluster.
+func triggerPlanRecompute(ctx context.Context, g *reaper.GrimReaper) {
+	log.Println("recomputing dry-run plan")
+	if err := g.ObserveRun(ctx); err != nil {
+		log.Printf("recomputing dry-run plan: %v", err)
+	}
+}
+
+// watchForPlanSignal recomputes the dry-run plan every time the process
+// receives SIGHUP. It blocks; callers should run it in its own goroutine.
+func watchForPlanSignal(ctx context.Context, g *reaper.GrimReaper) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	for range sigCh {
+		log.Println("received SIGHUP")
+		triggerPlanRecompute(ctx, g)
+	}
+}
+
+// shutdownExitCode is returned when grim-reaper exits in response to
+// SIGTERM or SIGINT, distinguishing a deliberate graceful shutdown from a
+// normal exit (0) or a fatal error (1).
+const shutdownExitCode = 130
+
+// watchForShutdownSignals waits for SIGTERM or SIGINT, then stops new
+// harvests from starting (via stopping), releases the leader election lock
+// and cancels every context derived from ctx by calling cancel, optionally
+// rolls back every node still marked for destruction but not yet
+// harvested, and exits. It blocks; callers should run it in its own
+// goroutine. The node grim-reaper is actively draining when the signal
+// arrives, if any, is left alone -- only nodes it hasn't started
+// harvesting yet are rolled back. The rollback itself runs against a fresh
+// context rather than the one just canceled, since it needs to complete
+// after shutdown has begun, not be aborted by it.
+func watchForShutdownSignals(ctx context.Context, cancel context.CancelFunc, g *reaper.GrimReaper, cfg *config.Config, stopping *atomic.Bool) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+	sig := <-sigCh
+	log.Printf("received %s; stopping new harvests and shutting down", sig)
+
+	stopping.Store(true)
+	cancel()
+
+	if cfg.RollbackOnShutdown {
+		reverted, err := g.UncordonMarkedNodes(context.Background(), "ShutdownRollback", "grim-reaper was shutting down and rolled back this node's partial run")
+		for _, name := range reverted {
+			log.Printf("rolled back node %s on shutdown", name)
+		}
+		if err != nil {
+			log.Printf("rolling back marked nodes on shutdown: %v", err)
+		}
+	}
+
+	os.Exit(shutdownExitCode)
+}
+
+// buildNotifierRouter wires up the platform and per-namespace Slack
+// webhooks configured on cfg. It returns nil if no webhooks are configured.
+func buildNotifierRouter(cfg *config.Config) *notify.Router {
+	if cfg.PlatformWebhookURL == "" && len(cfg.NamespaceWebhooks) == 0 {
+		return nil
+	}
+
+	var platform notify.Notifier
+	if cfg.PlatformWebhookURL != "" {
+		platform = notify.NewSlackNotifier(cfg.PlatformWebhookURL)
+	}
+
+	byNamespace := map[string]notify.Notifier{}
+	for ns, url := range cfg.NamespaceWebhooks {
+		byNamespace[ns] = notify.NewSlackNotifier(url)
+	}
+
+	return notify.NewRouter(platform, byNamespace)
+}
+
+// buildEventRecorder returns a Recorder that emits Events against the Node
+// objects grim-reaper acts on, so cluster operators can see reaper activity
+// in `kubectl describe node` and their cluster's event pipeline.
+func buildEventRecorder(clientset kubernetes.Interface) record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+	return broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "grim-reaper"})
+}
+
+// wireCloudProvider configures g.Cloud based on cfg.CloudProvider. It is a
+// no-op for "none".
+func wireCloudProvider(g *reaper.GrimReaper, cfg *config.Config) error {
+	g.CloudTerminationTimeout = cfg.CloudTerminationTimeout
+
+	switch cfg.CloudProvider {
+	case "", "none":
+		return nil
+	case "aws":
+		sess, err := session.NewSession(awssdk.NewConfig())
+		if err != nil {
+			return err
+		}
+		g.Cloud = aws.NewTerminator(autoscaling.New(sess), ec2.New(sess))
+		g.ProviderIDToInstanceID = aws.InstanceIDFromProviderID
+		return nil
+	case "gce":
+		svc, err := compute.NewService(context.Background())
+		if err != nil {
+			return err
+		}
+		g.Cloud = gce.NewTerminator(svc, cfg.GCEProject, cfg.GCEGroupName, cfg.GCERegion, cfg.GCEZone)
+		g.ProviderIDToInstanceID = gce.InstanceNameFromProviderID
+		return nil
+	case "azure":
+		authorizer, err := auth.NewAuthorizerFromEnvironment()
+		if err != nil {
+			return err
+		}
+		client := azcompute.NewVirtualMachineScaleSetVMsClient(cfg.AzureSubscriptionID)
+		client.Authorizer = authorizer
+		g.Cloud = azure.NewTerminator(client, cfg.AzureResourceGroup, cfg.AzureScaleSetName)
+		g.ProviderIDToInstanceID = azure.InstanceIDFromProviderID
+		return nil
+	default:
+		return fmt.Errorf("unsupported cloud provider %q", cfg.CloudProvider)
+	}
+}
+
+// writeDashboards renders the Grafana dashboard and PrometheusRule alerts
+// generated from grim-reaper's metric definitions into dir.
+func writeDashboards(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	dashboard, err := dashboards.Dashboard()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "grim-reaper-dashboard.json"), dashboard, 0o644); err != nil {
+		return err
+	}
+
+	alerts, err := dashboards.AlertRules()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "grim-reaper-alerts.json"), alerts, 0o644)
+}
+
+// printConfig writes cfg's fully resolved (flags/env/file merged),
+// secret-redacted configuration to w as indented JSON, so an operator can
+// verify what a running reaper is actually doing.
+func printConfig(w io.Writer, cfg *config.Config) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(cfg.Redacted())
+}
+
+// formatFeatureGates renders gates as a sorted, comma-separated list (e.g.
+// "CapacityCheck=true,CloudTerminate=false") so startup logs are
+// deterministic and diffable across runs.
+func formatFeatureGates(gates map[string]bool) string {
+	names := make([]string, 0, len(gates))
+	for name := range gates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%t", name, gates[name]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// shiftSubcommand consumes a leading subcommand (reap, plan, uncordon,
+// status, support-bundle, or version) from os.Args, if present, removing it
+// -- along with uncordon's positional node-name argument or
+// support-bundle's positional output-path argument -- so the rest of
+// main's flag parsing sees a normal flag-only argument list. Returns ("",
+// "") when no subcommand was given, which runs exactly like the bare
+// "reap" command, so existing flag-only invocations keep working
+// unchanged.
+func shiftSubcommand() (subcommand, arg string) {
+	if len(os.Args) < 2 {
+		return "", ""
+	}
+
+	switch os.Args[1] {
+	case "reap", "plan", "status", "version":
+		subcommand = os.Args[1]
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+		return subcommand, ""
+	case "uncordon", "support-bundle":
+		subcommand = os.Args[1]
+		if len(os.Args) > 2 && !strings.HasPrefix(os.Args[2], "-") {
+			arg = os.Args[2]
+			os.Args = append(os.Args[:1], os.Args[3:]...)
+		} else {
+			os.Args = append(os.Args[:1], os.Args[2:]...)
+		}
+		return subcommand, arg
+	default:
+		return "", ""
+	}
+}
+
+// runPlanCommand computes and prints a reaper.Plan in cfg.OutputFormat,
+// for the `grim-reaper plan` subcommand.
+func runPlanCommand(ctx context.Context, g *reaper.GrimReaper, cfg *config.Config) error {
+	plan, err := g.Plan(ctx)
+	if err != nil {
+		return fmt.Errorf("computing plan: %w", err)
+	}
+	return printPlan(os.Stdout, plan, cfg.OutputFormat)
+}
+
+func printPlan(w io.Writer, plan *reaper.Plan, format string) error {
+	switch format {
+	case "", "table":
+		return printPlanTable(w, plan)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	case "yaml":
+		return yaml.NewEncoder(w).Encode(plan)
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// printPlanTable renders plan the way `terraform plan` summarizes changes:
+// what would happen, and why anything else was left alone.
+func printPlanTable(w io.Writer, plan *reaper.Plan) error {
+	fmt.Fprintf(w, "Plan: %d node(s) to reap, %d passed over.\n", len(plan.ToReap), len(plan.PassedOver))
+
+	if len(plan.ToReap) > 0 {
+		fmt.Fprintln(w, "\nTo reap:")
+		for _, n := range plan.ToReap {
+			fmt.Fprintf(w, "  - %s  reason=%s pods=%d blocked=%d\n", n.Name, n.Reason, n.Pods, n.BlockedPods)
+			if n.StatefulBytes > 0 {
+				fmt.Fprintf(w, "      stateful=%d byte(s) estimated-replication-delay=%.0fs\n", n.StatefulBytes, n.EstimatedReplicationDelaySeconds)
+			}
+		}
+	}
+
+	if len(plan.PassedOver) > 0 {
+		fmt.Fprintln(w, "\nPassed over:")
+		for _, n := range plan.PassedOver {
+			fmt.Fprintf(w, "  - %s  reason=%s\n", n.Name, n.Reason)
+		}
+	}
+	return nil
+}
+
+// runUncordonCommand uncordons and clears every grim-reaper marker
+// (reap-reason/marked-at/marked-by annotations, the reap taint, and the
+// Reaping condition) from nodeName, or, if nodeName is empty, from every
+// node grim-reaper currently has marked for destruction. Useful for rolling
+// back an aborted run or one that used the wrong selector.
+func runUncordonCommand(ctx context.Context, g *reaper.GrimReaper, nodeName string) error {
+	const reason, message = "ManuallyUncordoned", "an operator ran `grim-reaper uncordon` to roll back this node's partial run"
+
+	if nodeName == "" {
+		reverted, err := g.UncordonMarkedNodes(ctx, reason, message)
+		for _, name := range reverted {
+			log.Printf("uncordoned node %s and cleared its grim-reaper markers", name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			log.Println("no nodes are currently marked for destruction")
+		}
+		return nil
+	}
+
+	nodes, err := g.Nodes.ListNodes(ctx, "")
+	if err != nil {
+		return fmt.Errorf("listing nodes: %w", err)
+	}
+	for i := range nodes {
+		if nodes[i].Name != nodeName {
+			continue
+		}
+		if err := g.ClearDestructionMarkers(ctx, &nodes[i], reason, message); err != nil {
+			return err
+		}
+		log.Printf("uncordoned node %s and cleared its grim-reaper markers", nodeName)
+		return nil
+	}
+	return fmt.Errorf("node %q not found", nodeName)
+}
+
+// inFlightNode describes a node a previous run marked for destruction, as
+// reported by the `status` subcommand.
+type inFlightNode struct {
+	Name                string `json:"name"`
+	Reason              string `json:"reason"`
+	MarkedAt            string `json:"markedAt"`
+	MarkedBy            string `json:"markedBy"`
+	HarvestFailureCount string `json:"harvestFailureCount,omitempty"`
+}
+
+// runStatusCommand lists every node currently marked for destruction (i.e.
+// cordoned by a run that hasn't harvested it yet, whether still in
+// progress or crashed mid-way), so an operator can see at a glance whether
+// `uncordon` needs to roll anything back.
+func runStatusCommand(ctx context.Context, g *reaper.GrimReaper, cfg *config.Config) error {
+	nodes, err := g.Nodes.ListNodes(ctx, cfg.NodeSelector)
+	if err != nil {
+		return fmt.Errorf("listing nodes: %w", err)
+	}
+
+	var inFlight []inFlightNode
+	for i := range nodes {
+		if nodes[i].Annotations[reaper.MarkedAtAnnotation] == "" {
+			continue
+		}
+		inFlight = append(inFlight, inFlightNode{
+			Name:                nodes[i].Name,
+			Reason:              nodes[i].Annotations[reaper.ReapReasonAnnotation],
+			MarkedAt:            nodes[i].Annotations[reaper.MarkedAtAnnotation],
+			MarkedBy:            nodes[i].Annotations[reaper.MarkedByAnnotation],
+			HarvestFailureCount: nodes[i].Annotations[reaper.HarvestFailureCountAnnotation],
+		})
+	}
+
+	return printStatus(os.Stdout, inFlight, cfg.OutputFormat)
+}
+
+func printStatus(w io.Writer, inFlight []inFlightNode, format string) error {
+	switch format {
+	case "", "table":
+		if len(inFlight) == 0 {
+			fmt.Fprintln(w, "no nodes currently marked for destruction")
+			return nil
+		}
+		fmt.Fprintf(w, "%d node(s) marked for destruction:\n\n", len(inFlight))
+		for _, n := range inFlight {
+			fmt.Fprintf(w, "  - %s  reason=%s markedAt=%s markedBy=%s\n", n.Name, n.Reason, n.MarkedAt, n.MarkedBy)
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(inFlight)
+	case "yaml":
+		return yaml.NewEncoder(w).Encode(inFlight)
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// serveConfigz runs an HTTP server on addr exposing the same
+// secret-redacted effective configuration as --print-config, for
+// operators to check against a running instance. It blocks; callers should
+// run it in its own goroutine.
+func serveConfigz(addr string, cfg *config.Config) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/configz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := printConfig(w, cfg); err != nil {
+			log.Printf("configz: encoding config: %v", err)
+		}
+	})
+	log.Printf("serving /configz on %s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Printf("configz server stopped: %v", err)
+	}
+}
+
+// healthStatus is the body served by /healthz and /readyz: enough for a
+// probe or a human to tell whether this replica is leading, when it last
+// finished a run without error, and whether a drain is currently
+// in-flight.
+type healthStatus struct {
+	Leader              bool       `json:"leader"`
+	LastSuccessfulRunAt *time.Time `json:"lastSuccessfulRunAt,omitempty"`
+	CurrentlyHarvesting []string   `json:"currentlyHarvesting,omitempty"`
+}
+
+// buildHealthStatus reports elector's leader status (always true when
+// elector is nil, i.e. leader election disabled), the FinishedAt timestamp
+// of the most recent error-free Run, and the node(s) (if any) Run is
+// currently mid-harvest on.
+func buildHealthStatus(g *reaper.GrimReaper, elector *leaderelection.Elector) healthStatus {
+	status := healthStatus{
+		Leader:              elector == nil || elector.IsLeader(),
+		CurrentlyHarvesting: g.CurrentNodes(),
+	}
+	if report := g.LastReport; report != nil && len(report.Errors) == 0 {
+		finishedAt := report.FinishedAt
+		status.LastSuccessfulRunAt = &finishedAt
+	}
+	return status
+}
+
+// serveStatus runs an HTTP server on addr exposing /metrics, /healthz,
+// /readyz, /status, and /plan, so every replica of an HA pair is
+// observable, not just the one currently reaping. A nil elector means
+// leader election is disabled and this replica is always considered the
+// leader. It blocks; callers should run it in its own goroutine.
+func serveStatus(ctx context.Context, addr string, g *reaper.GrimReaper, elector *leaderelection.Elector) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(buildHealthStatus(g, elector)); err != nil {
+			log.Printf("healthz: encoding response: %v", err)
+		}
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		status := buildHealthStatus(g, elector)
+		ready := g.LastReport == nil || len(g.LastReport.Errors) == 0
+		w.Header().Set("Content-Type", "application/json")
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(status); err != nil {
+			log.Printf("readyz: encoding response: %v", err)
+		}
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		leading := elector == nil || elector.IsLeader()
+		role := "standby"
+		if leading {
+			role = "leader"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]any{"role": role, "leader": leading}); err != nil {
+			log.Printf("status: encoding response: %v", err)
+		}
+	})
+	mux.HandleFunc("/plan", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		go triggerPlanRecompute(ctx, g)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	log.Printf("serving /metrics, /healthz, /readyz, /status, and /plan on %s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Printf("status server stopped: %v", err)
+	}
+}
+
+func newClientset(kubeconfig string) (kubernetes.Interface, error) {
+	restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(restConfig)
+}
+
+// newClientsetOrFake builds a real clientset, unless cfg.FakeCluster is
+// set, in which case it builds an in-memory synthetic cluster instead so
+// the pipeline can be exercised without a real one. cfg.FakeCluster may
+// name a synthetic spec, a recorded scenario, or a cluster-info dump; see
+// fakecluster.Load.
+func newClientsetOrFake(cfg *config.Config) (kubernetes.Interface, error) {
+	if cfg.FakeCluster == "" {
+		return newClientset(cfg.Kubeconfig)
+	}
+
+	clientset, err := fakecluster.Load(cfg.FakeCluster)
+	if err != nil {
+		return nil, fmt.Errorf("parsing --fake-cluster: %w", err)
+	}
+	log.Printf("using a fake cluster (--fake-cluster=%s); no real cluster will be touched", cfg.FakeCluster)
+	return clientset, nil
+}
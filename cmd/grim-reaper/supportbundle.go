@@ -0,0 +1,156 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/BrianKopp/grim-reaper/internal/config"
+	"github.com/BrianKopp/grim-reaper/internal/reaper"
+)
+
+// podSummary is a trimmed-down view of a pod for the support bundle --
+// enough to diagnose a stuck eviction without carrying container specs
+// (env vars, volume mounts) that might themselves hold secrets.
+type podSummary struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Node      string `json:"node"`
+	Phase     string `json:"phase"`
+	OwnerKind string `json:"ownerKind,omitempty"`
+}
+
+// runSupportBundleCommand collects the effective config, current
+// marked-node status and plan, node and pod descriptions, and (if
+// --report-file is configured) the last run report into a gzipped tarball
+// at path, for attaching to a bug report. Secrets never enter the bundle:
+// the config is redacted the same way --print-config redacts it, and pod
+// specs are summarized rather than included verbatim so a stray
+// secret-valued env var can't leak through.
+func runSupportBundleCommand(ctx context.Context, g *reaper.GrimReaper, cfg *config.Config, path string) error {
+	if path == "" {
+		path = "support-bundle.tar.gz"
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	if err := collectSupportBundle(ctx, tw, g, cfg); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("closing support bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("closing support bundle: %w", err)
+	}
+
+	log.Printf("wrote support bundle to %s", path)
+	return nil
+}
+
+func collectSupportBundle(ctx context.Context, tw *tar.Writer, g *reaper.GrimReaper, cfg *config.Config) error {
+	if err := addJSON(tw, "config.json", cfg.Redacted()); err != nil {
+		return err
+	}
+
+	nodes, err := g.Nodes.ListNodes(ctx, cfg.NodeSelector)
+	if err != nil {
+		return fmt.Errorf("listing nodes: %w", err)
+	}
+	if err := addJSON(tw, "nodes.json", nodes); err != nil {
+		return err
+	}
+
+	var marked []corev1.Node
+	var pods []podSummary
+	for i := range nodes {
+		if nodes[i].Annotations[reaper.MarkedAtAnnotation] == "" {
+			continue
+		}
+		marked = append(marked, nodes[i])
+
+		nodePods, err := g.Pods.ListPodsOnNode(ctx, nodes[i].Name)
+		if err != nil {
+			log.Printf("support-bundle: listing pods on node %s: %v", nodes[i].Name, err)
+			continue
+		}
+		for j := range nodePods {
+			pods = append(pods, summarizePod(&nodePods[j]))
+		}
+	}
+	if err := addJSON(tw, "marked-nodes.json", marked); err != nil {
+		return err
+	}
+	if err := addJSON(tw, "pods.json", pods); err != nil {
+		return err
+	}
+
+	if plan, err := g.Plan(ctx); err != nil {
+		log.Printf("support-bundle: computing plan: %v", err)
+	} else if err := addJSON(tw, "plan.json", plan); err != nil {
+		return err
+	}
+
+	if cfg.ReportFile != "" && cfg.ReportFile != "-" {
+		if report, err := os.ReadFile(cfg.ReportFile); err != nil {
+			log.Printf("support-bundle: reading --report-file %s: %v", cfg.ReportFile, err)
+		} else if err := addFile(tw, "last-run-report.json", report); err != nil {
+			return err
+		}
+	}
+
+	readme := fmt.Sprintf("grim-reaper support bundle, collected %s\n\n"+
+		"grim-reaper logs to stdout/stderr only; it doesn't keep a log file of\n"+
+		"its own, so attach `kubectl logs` output for the reaper pod(s)\n"+
+		"alongside this bundle if you have it.\n", time.Now().UTC().Format(time.RFC3339))
+	return addFile(tw, "README.txt", []byte(readme))
+}
+
+func summarizePod(pod *corev1.Pod) podSummary {
+	summary := podSummary{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		Node:      pod.Spec.NodeName,
+		Phase:     string(pod.Status.Phase),
+	}
+	if owners := pod.GetOwnerReferences(); len(owners) > 0 {
+		summary.OwnerKind = owners[0].Kind
+	}
+	return summary
+}
+
+func addJSON(tw *tar.Writer, name string, v interface{}) error {
+	encoded, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", name, err)
+	}
+	return addFile(tw, name, encoded)
+}
+
+func addFile(tw *tar.Writer, name string, contents []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(contents)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("writing %s header: %w", name, err)
+	}
+	_, err := tw.Write(contents)
+	return err
+}
@@ -0,0 +1,152 @@
+// Package aws terminates the EC2 instance backing a reaped node via its
+// Auto Scaling Group, so capacity actually shrinks instead of just sitting
+// drained.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+
+	"github.com/BrianKopp/grim-reaper/internal/cloud"
+)
+
+// Terminator terminates EC2 instances through their Auto Scaling Group.
+type Terminator struct {
+	ASG autoscalingiface.AutoScalingAPI
+
+	// EC2 is only consulted for cloud.DeleteModeDetachTerminate, to
+	// terminate an instance directly after it's been detached from its
+	// Auto Scaling Group. Unused (and may be left nil) for the other
+	// deletion modes, which terminate through the ASG API itself.
+	EC2 ec2iface.EC2API
+
+	// PollInterval is how often WaitForTermination checks instance state.
+	PollInterval time.Duration
+}
+
+// NewTerminator returns a Terminator backed by asg and ec2Client.
+func NewTerminator(asg autoscalingiface.AutoScalingAPI, ec2Client ec2iface.EC2API) *Terminator {
+	return &Terminator{ASG: asg, EC2: ec2Client, PollInterval: 10 * time.Second}
+}
+
+// InstanceIDFromProviderID extracts the EC2 instance ID from a Kubernetes
+// node's providerID, e.g. "aws:///us-east-1a/i-0123456789abcdef0".
+func InstanceIDFromProviderID(providerID string) (string, error) {
+	parts := strings.Split(providerID, "/")
+	if len(parts) == 0 {
+		return "", fmt.Errorf("empty providerID")
+	}
+	id := parts[len(parts)-1]
+	if !strings.HasPrefix(id, "i-") {
+		return "", fmt.Errorf("providerID %q does not look like an AWS instance", providerID)
+	}
+	return id, nil
+}
+
+// TerminateInstance terminates instanceID according to mode:
+//
+//   - cloud.DeleteModeShrink (the default) terminates instanceID via its
+//     Auto Scaling Group and decrements the ASG's desired capacity, so the
+//     group doesn't immediately launch a replacement.
+//   - cloud.DeleteModeRecycle terminates instanceID via its Auto Scaling
+//     Group without touching desired capacity, so the group launches a
+//     replacement.
+//   - cloud.DeleteModeDetachTerminate detaches instanceID from its Auto
+//     Scaling Group (also without touching desired capacity, so the group
+//     launches a replacement right away) and then terminates the detached
+//     instance directly via EC2. WaitForTermination's ASG-membership check
+//     will report this instance gone as soon as it's detached, before EC2
+//     actually finishes terminating it.
+func (t *Terminator) TerminateInstance(ctx context.Context, instanceID string, mode cloud.DeletionMode) error {
+	switch mode {
+	case cloud.DeleteModeRecycle:
+		_, err := t.ASG.TerminateInstanceInAutoScalingGroupWithContext(ctx, &autoscaling.TerminateInstanceInAutoScalingGroupInput{
+			InstanceId:                     aws.String(instanceID),
+			ShouldDecrementDesiredCapacity: aws.Bool(false),
+		})
+		return err
+	case cloud.DeleteModeDetachTerminate:
+		group, err := t.autoScalingGroupFor(ctx, instanceID)
+		if err != nil {
+			return err
+		}
+		if _, err := t.ASG.DetachInstancesWithContext(ctx, &autoscaling.DetachInstancesInput{
+			InstanceIds:                    []*string{aws.String(instanceID)},
+			AutoScalingGroupName:           aws.String(group),
+			ShouldDecrementDesiredCapacity: aws.Bool(false),
+		}); err != nil {
+			return fmt.Errorf("detaching instance %s from %s: %w", instanceID, group, err)
+		}
+		_, err = t.EC2.TerminateInstancesWithContext(ctx, &ec2.TerminateInstancesInput{InstanceIds: []*string{aws.String(instanceID)}})
+		return err
+	default:
+		_, err := t.ASG.TerminateInstanceInAutoScalingGroupWithContext(ctx, &autoscaling.TerminateInstanceInAutoScalingGroupInput{
+			InstanceId:                     aws.String(instanceID),
+			ShouldDecrementDesiredCapacity: aws.Bool(true),
+		})
+		return err
+	}
+}
+
+// ProtectFromScaleIn sets the instance-protection flag on instanceID within
+// its Auto Scaling Group, preventing the ASG from terminating it out from
+// under a drain that's already in progress.
+func (t *Terminator) ProtectFromScaleIn(ctx context.Context, instanceID string, protected bool) error {
+	group, err := t.autoScalingGroupFor(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.ASG.SetInstanceProtectionWithContext(ctx, &autoscaling.SetInstanceProtectionInput{
+		InstanceIds:          []*string{aws.String(instanceID)},
+		AutoScalingGroupName: aws.String(group),
+		ProtectedFromScaleIn: aws.Bool(protected),
+	})
+	return err
+}
+
+// autoScalingGroupFor returns the name of the Auto Scaling Group that owns
+// instanceID.
+func (t *Terminator) autoScalingGroupFor(ctx context.Context, instanceID string) (string, error) {
+	out, err := t.ASG.DescribeAutoScalingInstancesWithContext(ctx, &autoscaling.DescribeAutoScalingInstancesInput{
+		InstanceIds: []*string{aws.String(instanceID)},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(out.AutoScalingInstances) == 0 {
+		return "", fmt.Errorf("instance %s is not part of any Auto Scaling Group", instanceID)
+	}
+	return aws.StringValue(out.AutoScalingInstances[0].AutoScalingGroupName), nil
+}
+
+// WaitForTermination blocks until instanceID is no longer visible in any
+// Auto Scaling Group, ctx is canceled, or timeout elapses.
+func (t *Terminator) WaitForTermination(ctx context.Context, instanceID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		out, err := t.ASG.DescribeAutoScalingInstancesWithContext(ctx, &autoscaling.DescribeAutoScalingInstancesInput{
+			InstanceIds: []*string{aws.String(instanceID)},
+		})
+		if err != nil {
+			return err
+		}
+		if len(out.AutoScalingInstances) == 0 {
+			return nil
+		}
+		select {
+		case <-time.After(t.PollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("timed out waiting for instance %s to terminate", instanceID)
+}
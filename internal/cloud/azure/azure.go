@@ -0,0 +1,90 @@
+// Package azure terminates the VM backing a reaped node by deleting its
+// instance from the owning Virtual Machine Scale Set (VMSS), so AKS node
+// pools backed by scale sets actually shrink after a drain.
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+
+	"github.com/BrianKopp/grim-reaper/internal/cloud"
+)
+
+// Terminator deletes instances from their Virtual Machine Scale Set.
+type Terminator struct {
+	VMSS          compute.VirtualMachineScaleSetVMsClient
+	ResourceGroup string
+	ScaleSetName  string
+	PollInterval  time.Duration
+}
+
+// NewTerminator returns a Terminator for the VMSS identified by
+// resourceGroup and scaleSetName.
+func NewTerminator(client compute.VirtualMachineScaleSetVMsClient, resourceGroup, scaleSetName string) *Terminator {
+	return &Terminator{VMSS: client, ResourceGroup: resourceGroup, ScaleSetName: scaleSetName, PollInterval: 10 * time.Second}
+}
+
+// InstanceIDFromProviderID extracts the VMSS instance ID from a Kubernetes
+// node's providerID, e.g.
+// "azure:///subscriptions/.../virtualMachineScaleSets/mypool/virtualMachines/3".
+func InstanceIDFromProviderID(providerID string) (string, error) {
+	parts := strings.Split(providerID, "/")
+	if len(parts) == 0 {
+		return "", fmt.Errorf("empty providerID")
+	}
+	return parts[len(parts)-1], nil
+}
+
+// ProtectFromScaleIn sets (or clears) the instance's protection-from-
+// scale-in flag, so the scale set's own autoscaler doesn't delete the
+// instance out from under an in-progress drain.
+func (t *Terminator) ProtectFromScaleIn(ctx context.Context, instanceID string, protected bool) error {
+	vm, err := t.VMSS.Get(ctx, t.ResourceGroup, t.ScaleSetName, instanceID, "")
+	if err != nil {
+		return err
+	}
+	if vm.ProtectionPolicy == nil {
+		vm.ProtectionPolicy = &compute.VirtualMachineScaleSetVMProtectionPolicy{}
+	}
+	vm.ProtectionPolicy.ProtectFromScaleIn = &protected
+
+	_, err = t.VMSS.Update(ctx, t.ResourceGroup, t.ScaleSetName, instanceID, vm)
+	return err
+}
+
+// TerminateInstance deletes instanceID from the scale set, which also
+// decrements the scale set's capacity. Only cloud.DeleteModeShrink (the
+// default) is supported: unlike an ASG or a GCE managed instance group, a
+// VMSS has no concept of detaching an instance from the set while leaving
+// its capacity (and thus the set's intent to replace it) alone, so
+// cloud.DeleteModeRecycle and cloud.DeleteModeDetachTerminate are rejected
+// rather than silently behaving like a shrink.
+func (t *Terminator) TerminateInstance(ctx context.Context, instanceID string, mode cloud.DeletionMode) error {
+	if mode != "" && mode != cloud.DeleteModeShrink {
+		return fmt.Errorf("azure VMSS terminator does not support deletion mode %q", mode)
+	}
+	_, err := t.VMSS.Delete(ctx, t.ResourceGroup, t.ScaleSetName, instanceID, nil)
+	return err
+}
+
+// WaitForTermination blocks until instanceID no longer exists in the scale
+// set, ctx is canceled, or timeout elapses.
+func (t *Terminator) WaitForTermination(ctx context.Context, instanceID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		_, err := t.VMSS.Get(ctx, t.ResourceGroup, t.ScaleSetName, instanceID, "")
+		if err != nil {
+			return nil
+		}
+		select {
+		case <-time.After(t.PollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("timed out waiting for instance %s to terminate", instanceID)
+}
@@ -0,0 +1,72 @@
+// Package cloud defines the abstraction grim-reaper uses to remove the
+// compute instance backing a reaped node, so AWS, GCP, and Azure backends
+// can be added behind a common interface and picked at runtime.
+package cloud
+
+import (
+	"context"
+	"time"
+)
+
+// DeletionMode controls how Provider.TerminateInstance affects the
+// instance's owning group (ASG / MIG / VMSS) capacity, since "shrink the
+// cluster" and "replace this instance with a fresh one" are different
+// operator intents that the same Harvest step needs to support explicitly.
+type DeletionMode string
+
+const (
+	// DeleteModeShrink terminates the instance and decrements the
+	// group's desired/target size, so the freed capacity doesn't come
+	// back. This is the default, matching grim-reaper's behavior before
+	// DeletionMode existed.
+	DeleteModeShrink DeletionMode = "shrink"
+
+	// DeleteModeRecycle terminates the instance without touching the
+	// group's desired/target size, so the group launches a replacement
+	// -- "recycle this capacity slot" rather than "shrink the cluster".
+	DeleteModeRecycle DeletionMode = "recycle"
+
+	// DeleteModeDetachTerminate detaches the instance from its group
+	// (without changing desired/target size, so the group launches a
+	// replacement right away) and then terminates the detached instance
+	// directly, rather than leaving it running outside the group's
+	// management.
+	DeleteModeDetachTerminate DeletionMode = "detach-terminate"
+)
+
+// Provider terminates the instance backing a reaped node. Every method
+// takes a context.Context so a timeout or a lost leader election lease
+// cancels an in-flight cloud API call instead of leaving it to run to
+// completion on its own.
+type Provider interface {
+	// TerminateInstance begins terminating instanceID according to mode.
+	// A provider that can't support a given mode (e.g. it has no concept
+	// of detaching an instance from its group) returns an error rather
+	// than silently falling back to a different mode.
+	TerminateInstance(ctx context.Context, instanceID string, mode DeletionMode) error
+
+	// WaitForTermination blocks until instanceID is confirmed gone, ctx
+	// is canceled, or timeout elapses, whichever comes first.
+	WaitForTermination(ctx context.Context, instanceID string, timeout time.Duration) error
+
+	// ProtectFromScaleIn marks (or unmarks) instanceID as protected from
+	// the provider's own scale-in activity, so an autoscaler doesn't race
+	// grim-reaper's own drain-then-terminate sequence.
+	ProtectFromScaleIn(ctx context.Context, instanceID string, protected bool) error
+}
+
+// NoopProvider is the default Provider: it does nothing, for clusters that
+// manage their own instance lifecycle or run on bare metal.
+type NoopProvider struct{}
+
+func (NoopProvider) TerminateInstance(ctx context.Context, instanceID string, mode DeletionMode) error {
+	return nil
+}
+
+func (NoopProvider) WaitForTermination(ctx context.Context, instanceID string, timeout time.Duration) error {
+	return nil
+}
+
+func (NoopProvider) ProtectFromScaleIn(ctx context.Context, instanceID string, protected bool) error {
+	return nil
+}
@@ -0,0 +1,250 @@
+// Package gce terminates the GCE instance backing a reaped node by
+// deleting it from its managed instance group, so the group doesn't
+// immediately recreate the capacity grim-reaper just freed.
+package gce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+
+	"github.com/BrianKopp/grim-reaper/internal/cloud"
+)
+
+// Terminator deletes instances from their managed instance group (MIG),
+// handling both zonal and regional groups.
+type Terminator struct {
+	Compute   *compute.Service
+	Project   string
+	GroupName string
+
+	// Region is set for a regional MIG, Zone for a zonal one. Exactly one
+	// should be non-empty.
+	Region string
+	Zone   string
+
+	PollInterval time.Duration
+}
+
+// NewTerminator returns a Terminator for the MIG identified by project,
+// groupName, and either region or zone.
+func NewTerminator(svc *compute.Service, project, groupName, region, zone string) *Terminator {
+	return &Terminator{
+		Compute:      svc,
+		Project:      project,
+		GroupName:    groupName,
+		Region:       region,
+		Zone:         zone,
+		PollInterval: 10 * time.Second,
+	}
+}
+
+// InstanceNameFromProviderID extracts the instance name from a Kubernetes
+// node's providerID, e.g. "gce://my-project/us-central1-a/my-instance".
+func InstanceNameFromProviderID(providerID string) (string, error) {
+	parts := strings.Split(providerID, "/")
+	if len(parts) < 1 {
+		return "", fmt.Errorf("empty providerID")
+	}
+	return parts[len(parts)-1], nil
+}
+
+func (t *Terminator) zonalInstanceURL(zone, instanceID string) string {
+	return fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/zones/%s/instances/%s", t.Project, zone, instanceID)
+}
+
+// instanceSelfLink returns the fully qualified URL identifying instanceID,
+// the form TerminateInstance/abandonInstance need for their Instances
+// list. For a zonal MIG this is cheap to build directly from t.Zone; for a
+// regional MIG, instances are still zonal resources, but which zone they
+// landed in isn't known up front, so it's resolved via the group's own
+// managed-instance list.
+func (t *Terminator) instanceSelfLink(ctx context.Context, instanceID string) (string, error) {
+	if t.Zone != "" {
+		return t.zonalInstanceURL(t.Zone, instanceID), nil
+	}
+	return t.regionalInstanceSelfLink(ctx, instanceID)
+}
+
+// errInstanceNotManaged signals that regionalInstanceSelfLink's ListManagedInstances
+// call succeeded but didn't list instanceID -- the genuine "it's gone"
+// signal callers distinguish from a transient ListManagedInstances failure.
+var errInstanceNotManaged = errors.New("instance not found among group's managed instances")
+
+// regionalInstanceSelfLink looks up instanceID's self-link (which encodes
+// its actual zone) among t.GroupName's managed instances. Returns
+// errInstanceNotManaged if instanceID isn't currently a managed instance
+// of the group -- e.g. because it's already been deleted.
+func (t *Terminator) regionalInstanceSelfLink(ctx context.Context, instanceID string) (string, error) {
+	resp, err := t.Compute.RegionInstanceGroupManagers.ListManagedInstances(t.Project, t.Region, t.GroupName).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("listing managed instances in %s: %w", t.GroupName, err)
+	}
+	for _, mi := range resp.ManagedInstances {
+		if mi.Name == instanceID {
+			return mi.Instance, nil
+		}
+	}
+	return "", fmt.Errorf("instance %s: %w", instanceID, errInstanceNotManaged)
+}
+
+// instanceZone extracts the zone segment (e.g. "us-central1-a") from a
+// zonal instance self-link.
+func instanceZone(selfLink string) string {
+	parts := strings.Split(selfLink, "/")
+	for i, part := range parts {
+		if part == "zones" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// resolveZone returns the zone instanceID actually lives in: t.Zone
+// directly for a zonal MIG, or, for a regional one, whatever zone its
+// self-link in the group's managed-instance list reports.
+func (t *Terminator) resolveZone(ctx context.Context, instanceID string) (string, error) {
+	if t.Zone != "" {
+		return t.Zone, nil
+	}
+	selfLink, err := t.regionalInstanceSelfLink(ctx, instanceID)
+	if err != nil {
+		return "", err
+	}
+	zone := instanceZone(selfLink)
+	if zone == "" {
+		return "", fmt.Errorf("could not determine zone for instance %s from %q", instanceID, selfLink)
+	}
+	return zone, nil
+}
+
+// isNotFound reports whether err is a googleapi 404, the genuine signal
+// that an instance no longer exists, as opposed to a transient error
+// (auth, rate limiting, network) that happens to surface the same way a
+// missing-instance error would if checked less precisely.
+func isNotFound(err error) bool {
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code == http.StatusNotFound
+	}
+	return false
+}
+
+// TerminateInstance removes instanceID according to mode:
+//
+//   - cloud.DeleteModeShrink (the default) deletes instanceID through its
+//     managed instance group, which also shrinks the group's target size.
+//   - cloud.DeleteModeRecycle deletes instanceID directly, bypassing the
+//     group, so the group's autohealer notices the missing instance and
+//     replaces it.
+//   - cloud.DeleteModeDetachTerminate abandons instanceID from the group
+//     (which, like direct deletion, leaves target size untouched and lets
+//     the group replace it right away) and then deletes the abandoned
+//     instance directly.
+func (t *Terminator) TerminateInstance(ctx context.Context, instanceID string, mode cloud.DeletionMode) error {
+	switch mode {
+	case cloud.DeleteModeRecycle:
+		return t.deleteInstanceDirect(ctx, instanceID)
+	case cloud.DeleteModeDetachTerminate:
+		if err := t.abandonInstance(ctx, instanceID); err != nil {
+			return fmt.Errorf("abandoning instance %s from %s: %w", instanceID, t.GroupName, err)
+		}
+		return t.deleteInstanceDirect(ctx, instanceID)
+	default:
+		selfLink, err := t.instanceSelfLink(ctx, instanceID)
+		if err != nil {
+			return err
+		}
+		if t.Region != "" {
+			req := &compute.RegionInstanceGroupManagersDeleteInstancesRequest{
+				Instances: []string{selfLink},
+			}
+			_, err := t.Compute.RegionInstanceGroupManagers.DeleteInstances(t.Project, t.Region, t.GroupName, req).Context(ctx).Do()
+			return err
+		}
+		req := &compute.InstanceGroupManagersDeleteInstancesRequest{
+			Instances: []string{selfLink},
+		}
+		_, err = t.Compute.InstanceGroupManagers.DeleteInstances(t.Project, t.Zone, t.GroupName, req).Context(ctx).Do()
+		return err
+	}
+}
+
+// abandonInstance removes instanceID from the managed instance group's
+// membership without deleting it or changing the group's target size.
+func (t *Terminator) abandonInstance(ctx context.Context, instanceID string) error {
+	selfLink, err := t.instanceSelfLink(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	if t.Region != "" {
+		req := &compute.RegionInstanceGroupManagersAbandonInstancesRequest{
+			Instances: []string{selfLink},
+		}
+		_, err := t.Compute.RegionInstanceGroupManagers.AbandonInstances(t.Project, t.Region, t.GroupName, req).Context(ctx).Do()
+		return err
+	}
+	req := &compute.InstanceGroupManagersAbandonInstancesRequest{
+		Instances: []string{selfLink},
+	}
+	_, err = t.Compute.InstanceGroupManagers.AbandonInstances(t.Project, t.Zone, t.GroupName, req).Context(ctx).Do()
+	return err
+}
+
+// deleteInstanceDirect deletes instanceID without going through a managed
+// instance group at all.
+func (t *Terminator) deleteInstanceDirect(ctx context.Context, instanceID string) error {
+	zone, err := t.resolveZone(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	_, err = t.Compute.Instances.Delete(t.Project, zone, instanceID).Context(ctx).Do()
+	return err
+}
+
+// WaitForTermination blocks until instanceID no longer exists, ctx is
+// canceled, or timeout elapses. Only a genuine 404 counts as "terminated";
+// any other error (auth, rate limiting, a network blip) is logged and
+// retried rather than mistaken for success.
+func (t *Terminator) WaitForTermination(ctx context.Context, instanceID string, timeout time.Duration) error {
+	zone, err := t.resolveZone(ctx, instanceID)
+	if errors.Is(err, errInstanceNotManaged) {
+		// instanceID is no longer a managed instance of a regional MIG at
+		// all, the same terminal state Instances.Get 404ing would report.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("resolving zone for instance %s: %w", instanceID, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		_, err := t.Compute.Instances.Get(t.Project, zone, instanceID).Context(ctx).Do()
+		if isNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			log.Printf("checking status of instance %s: %v", instanceID, err)
+		}
+		select {
+		case <-time.After(t.PollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("timed out waiting for instance %s to terminate", instanceID)
+}
+
+// ProtectFromScaleIn is not supported by GCE managed instance groups; it
+// always returns nil so callers using a shared CloudProvider interface
+// don't need to special-case this backend.
+func (t *Terminator) ProtectFromScaleIn(ctx context.Context, instanceID string, protected bool) error {
+	return nil
+}
@@ -0,0 +1,201 @@
+package gce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+
+	"github.com/BrianKopp/grim-reaper/internal/cloud"
+)
+
+func TestInstanceNameFromProviderID(t *testing.T) {
+	got, err := InstanceNameFromProviderID("gce://my-project/us-central1-a/my-instance")
+	if err != nil {
+		t.Fatalf("InstanceNameFromProviderID: unexpected error: %v", err)
+	}
+	if got != "my-instance" {
+		t.Fatalf("InstanceNameFromProviderID = %q, want %q", got, "my-instance")
+	}
+}
+
+// fakeComputeServer stands in for the GCE compute API: it records every
+// request path it receives, answers a regional MIG's listManagedInstances
+// call with a single managed instance (so regional zone resolution has
+// something real to resolve against), and answers every other call with a
+// successful, empty operation.
+type fakeComputeServer struct {
+	paths               []string
+	managedInstanceZone string
+	managedInstanceName string
+}
+
+func (f *fakeComputeServer) handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.paths = append(f.paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+
+		if strings.Contains(r.URL.Path, "listManagedInstances") {
+			selfLink := fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/my-project/zones/%s/instances/%s", f.managedInstanceZone, f.managedInstanceName)
+			json.NewEncoder(w).Encode(&compute.RegionInstanceGroupManagersListInstancesResponse{
+				ManagedInstances: []*compute.ManagedInstance{{Name: f.managedInstanceName, Instance: selfLink}},
+			})
+			return
+		}
+		w.Write([]byte(`{"status":"DONE"}`))
+	}
+}
+
+func newTestTerminator(t *testing.T, region, zone string, server *fakeComputeServer) *Terminator {
+	t.Helper()
+	httpServer := httptest.NewServer(server.handler())
+	t.Cleanup(httpServer.Close)
+
+	svc, err := compute.NewService(context.Background(),
+		option.WithEndpoint(httpServer.URL+"/"),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(httpServer.Client()),
+	)
+	if err != nil {
+		t.Fatalf("building fake compute service: %v", err)
+	}
+
+	return NewTerminator(svc, "my-project", "my-mig", region, zone)
+}
+
+func TestTerminateInstanceZonal(t *testing.T) {
+	server := &fakeComputeServer{}
+	term := newTestTerminator(t, "", "us-central1-a", server)
+
+	if err := term.TerminateInstance(context.Background(), "my-instance", cloud.DeleteModeShrink); err != nil {
+		t.Fatalf("TerminateInstance: unexpected error: %v", err)
+	}
+
+	if len(server.paths) != 1 || !containsAll(server.paths[0], "/zones/us-central1-a/", "/instanceGroupManagers/my-mig/deleteInstances") {
+		t.Fatalf("TerminateInstance hit %v, want a single zonal deleteInstances call", server.paths)
+	}
+}
+
+func TestTerminateInstanceRegional(t *testing.T) {
+	server := &fakeComputeServer{managedInstanceZone: "us-central1-b", managedInstanceName: "my-instance"}
+	term := newTestTerminator(t, "us-central1", "", server)
+
+	if err := term.TerminateInstance(context.Background(), "my-instance", cloud.DeleteModeShrink); err != nil {
+		t.Fatalf("TerminateInstance: unexpected error: %v", err)
+	}
+
+	if len(server.paths) != 2 {
+		t.Fatalf("TerminateInstance hit %v, want a listManagedInstances call followed by a regional deleteInstances call", server.paths)
+	}
+	if !strings.Contains(server.paths[0], "listManagedInstances") {
+		t.Fatalf("TerminateInstance's first call was %s, want listManagedInstances (to resolve the instance's actual zone)", server.paths[0])
+	}
+	if !containsAll(server.paths[1], "/regions/us-central1/", "/instanceGroupManagers/my-mig/deleteInstances") {
+		t.Fatalf("TerminateInstance's second call was %s, want a regional deleteInstances call", server.paths[1])
+	}
+}
+
+func TestAbandonInstanceRegional(t *testing.T) {
+	server := &fakeComputeServer{managedInstanceZone: "us-central1-b", managedInstanceName: "my-instance"}
+	term := newTestTerminator(t, "us-central1", "", server)
+
+	if err := term.abandonInstance(context.Background(), "my-instance"); err != nil {
+		t.Fatalf("abandonInstance: unexpected error: %v", err)
+	}
+
+	if len(server.paths) != 2 || !containsAll(server.paths[1], "/regions/us-central1/", "/instanceGroupManagers/my-mig/abandonInstances") {
+		t.Fatalf("abandonInstance hit %v, want a listManagedInstances call followed by a regional abandonInstances call", server.paths)
+	}
+}
+
+// TestWaitForTerminationRegionalUsesResolvedZone confirms WaitForTermination
+// polls Instances.Get against the instance's actual zone (resolved via the
+// regional MIG's managed-instance list), not t.Region, and that a genuine
+// 404 -- not just any error -- is what ends the wait.
+func TestWaitForTerminationRegionalUsesResolvedZone(t *testing.T) {
+	var gets atomic.Int32
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "listManagedInstances"):
+			json.NewEncoder(w).Encode(&compute.RegionInstanceGroupManagersListInstancesResponse{
+				ManagedInstances: []*compute.ManagedInstance{{
+					Name:     "my-instance",
+					Instance: "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-b/instances/my-instance",
+				}},
+			})
+		case strings.Contains(r.URL.Path, "/zones/us-central1-b/instances/my-instance"):
+			gets.Add(1)
+			if gets.Load() < 2 {
+				json.NewEncoder(w).Encode(&compute.Instance{Name: "my-instance"})
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": 404, "message": "not found"}})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(httpServer.Close)
+
+	svc, err := compute.NewService(context.Background(),
+		option.WithEndpoint(httpServer.URL+"/"),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(httpServer.Client()),
+	)
+	if err != nil {
+		t.Fatalf("building fake compute service: %v", err)
+	}
+
+	term := NewTerminator(svc, "my-project", "my-mig", "us-central1", "")
+	term.PollInterval = time.Millisecond
+
+	if err := term.WaitForTermination(context.Background(), "my-instance", time.Second); err != nil {
+		t.Fatalf("WaitForTermination: unexpected error: %v", err)
+	}
+	if gets.Load() < 2 {
+		t.Fatalf("Instances.Get called %d times, want at least 2 (one non-404, then a 404)", gets.Load())
+	}
+}
+
+// TestWaitForTerminationNotManagedIsTerminated confirms that an instance no
+// longer present in a regional MIG's managed-instance list is treated as
+// already terminated, without needing to resolve a zone to double-check.
+func TestWaitForTerminationNotManagedIsTerminated(t *testing.T) {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&compute.RegionInstanceGroupManagersListInstancesResponse{})
+	}))
+	t.Cleanup(httpServer.Close)
+
+	svc, err := compute.NewService(context.Background(),
+		option.WithEndpoint(httpServer.URL+"/"),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(httpServer.Client()),
+	)
+	if err != nil {
+		t.Fatalf("building fake compute service: %v", err)
+	}
+
+	term := NewTerminator(svc, "my-project", "my-mig", "us-central1", "")
+	if err := term.WaitForTermination(context.Background(), "my-instance", time.Second); err != nil {
+		t.Fatalf("WaitForTermination: unexpected error: %v", err)
+	}
+}
+
+func containsAll(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,937 @@
+// Package config defines the tunable settings for a grim-reaper run and
+// how they are loaded from the command line.
+//
+// Every flag can also be set via a GRIM_REAPER_<FLAG_NAME> environment
+// variable (dashes become underscores, e.g. --node-selector becomes
+// GRIM_REAPER_NODE_SELECTOR), or via a `--config` YAML file. Precedence is
+// flags > env > config file.
+package config
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds every setting that controls how a run selects and reaps
+// nodes.
+type Config struct {
+	Kubeconfig string
+
+	// NodeSelector restricts which nodes are even considered for reaping.
+	NodeSelector string
+
+	// Strategy picks how candidate nodes are ordered for reaping.
+	// Supported values: "oldest", "newest", "random", "emptiest",
+	// "fragmentation", "most-restarts", "weighted".
+	Strategy string
+
+	// ReapFraction is the fraction of eligible nodes reaped in a single run.
+	ReapFraction float64
+
+	// MinNodes is the smallest the cluster is allowed to shrink to -- an
+	// absolute count ("3") or a percentage of the current fleet size
+	// ("80%"), resolved fresh by ParseMinNodes each time it's checked, so
+	// the same config works unchanged across clusters of different sizes.
+	MinNodes string
+
+	// MinNodesRequireReady makes MinNodes count only nodes whose Ready
+	// condition is currently True, re-checked right before each harvest.
+	// Without it, MinNodes only ever counted nodes that existed, Ready or
+	// not, so a cluster already limping along on a few NotReady nodes
+	// could have its last healthy capacity reaped out from under it.
+	MinNodesRequireReady bool
+
+	// MaxNodesDelete caps how many nodes a single run will reap, regardless
+	// of ReapFraction.
+	MaxNodesDelete int
+
+	// MaxUnavailable is a PodDisruptionBudget-style budget on top of
+	// MaxNodesDelete: an absolute count ("5") or a percentage ("10%") of
+	// the selected node pool, computed fresh each run. It counts nodes
+	// already cordoned by something other than grim-reaper against the
+	// budget too, so a cluster an operator has already partially cordoned
+	// by hand doesn't get pushed further past the intended ceiling. Empty
+	// disables the check, leaving MaxNodesDelete/ReapFraction/MinNodes as
+	// the only budgets.
+	MaxUnavailable string
+
+	// DryRun computes the reap plan and logs it without cordoning, draining,
+	// or deleting anything.
+	DryRun bool
+
+	// Canary, when true, harvests exactly one node from a batch of more
+	// than one, watches the cluster for CanaryVerificationPeriod, and
+	// only dispatches the rest of the batch if that canary node was
+	// absorbed cleanly -- no pod stuck Pending past VerifyPendingPodGrace,
+	// no node gone NotReady. A problem during the canary stops the run
+	// before any other node is touched.
+	Canary bool
+	// CanaryVerificationPeriod is how long Canary watches the cluster
+	// after the canary harvest before proceeding. Zero disables the
+	// verification window entirely, proceeding as soon as the harvest
+	// itself succeeds.
+	CanaryVerificationPeriod time.Duration
+
+	LeaderElection bool
+	LeaseNamespace string
+	LeaseName      string
+
+	// LockType selects the Kubernetes object backing the leader election
+	// lock. "leases" is the only value client-go v0.28 still supports --
+	// it dropped the ConfigMap and dual ConfigMap+Lease backends that
+	// used to exist here for migrating a fleet off the deprecated
+	// ConfigMap lock.
+	LockType string
+
+	// StatusAddr, if set, serves /metrics, /healthz, /readyz, /status, and
+	// /plan over HTTP on this address. /status reports "leader" or "standby" so every
+	// replica of an HA pair is observable, not just the one currently
+	// reaping. Empty disables the server.
+	StatusAddr string
+
+	// StaleMarkerTTL bounds how long GetNodesToReap will keep resuming the
+	// harvest of a node marked for destruction by a previous run. Past this
+	// age, the node is assumed abandoned rather than merely slow: it's
+	// uncordoned and its markers cleared instead of resumed, so an aborted
+	// run doesn't leave it unschedulable forever. 0 disables the GC.
+	StaleMarkerTTL time.Duration
+
+	// ReapTaint, if set, applies this NoSchedule taint (e.g.
+	// "grim-reaper.io/reaping=true", or "grim-reaper.io/reaping=$RUNID" to
+	// tag the taint with the run ID) to a node in MarkNodesForDestruction,
+	// in addition to cordoning it, so other controllers and humans can see
+	// why the node is being removed from a `kubectl describe node`, and so
+	// pods that explicitly tolerate it can still be scheduled there (a
+	// cordon blocks scheduling unconditionally). Empty disables the taint.
+	ReapTaint string
+
+	// RunInterval is how long the process sleeps between runs.
+	RunInterval time.Duration
+
+	// PlatformWebhookURL, if set, receives a notification for every pod
+	// eviction regardless of namespace.
+	PlatformWebhookURL string
+
+	// NamespaceWebhooks additionally routes eviction notifications for a
+	// namespace to its owning team, e.g. "checkout=https://hooks.slack.com/...".
+	NamespaceWebhooks map[string]string
+
+	// CloudProvider selects which cloud integration terminates the instance
+	// backing a reaped node. Supported values: "none", "aws", "gce",
+	// "azure".
+	CloudProvider           string
+	CloudTerminationTimeout time.Duration
+
+	// InstanceDeletionMode selects how Cloud.TerminateInstance affects the
+	// instance's owning group's capacity: "shrink" (the default) to
+	// decrement desired/target size, "recycle" to leave it alone so the
+	// group launches a replacement, or "detach-terminate" to detach the
+	// instance from the group (also leaving capacity alone) and then
+	// terminate it directly. Not every provider supports every mode; see
+	// cloud.DeletionMode.
+	InstanceDeletionMode string
+
+	// AsyncTermination, if set, hands instance termination off to a
+	// background queue once a node is drained and deleted, instead of
+	// blocking the harvest loop on Cloud.TerminateInstance and
+	// WaitForTermination. Lets a slow cloud API fall behind without
+	// stalling the next node's drain; failures are retried independently
+	// via TerminationMaxRetries.
+	AsyncTermination bool
+
+	// TerminationMaxRetries caps how many times the async terminator
+	// retries a single instance's termination before giving up. 0 means
+	// unlimited. Ignored unless AsyncTermination is set.
+	TerminationMaxRetries int
+
+	// PreAnnounceDuration, if non-zero, is how long grim-reaper waits after
+	// cordoning and notifying a node's namespaces before draining begins.
+	PreAnnounceDuration time.Duration
+
+	// EvictionBackoffPolicy selects the retry strategy used when an
+	// eviction is rejected, typically by a PodDisruptionBudget. Supported
+	// values: "constant", "exponential", "decorrelated-jitter",
+	// "jittered-exponential". A server-supplied Retry-After always takes
+	// priority over whichever policy is configured (see Evict).
+	EvictionBackoffPolicy string
+	EvictionBackoffBase   time.Duration
+	EvictionBackoffMax    time.Duration
+	EvictionMaxRetries    int
+
+	// ForceDeleteAfterTimeout, when true, deletes a pod directly (bypassing
+	// the eviction API) once its eviction retries are exhausted, rather
+	// than failing the whole node's drain over one stuck PDB or broken
+	// admission webhook -- mirroring `kubectl drain --disable-eviction`.
+	// The deletion still honors ForceDeleteGracePeriod, and is always
+	// logged loudly and notified, since it skips the PDB protections the
+	// eviction API exists to enforce.
+	ForceDeleteAfterTimeout bool
+	// ForceDeleteGracePeriod is the grace period given to a pod that's
+	// force-deleted after ForceDeleteAfterTimeout kicks in.
+	ForceDeleteGracePeriod time.Duration
+
+	// ExtendedGracePeriod, when enabled, lets Evict honor a pod's own
+	// terminationGracePeriodSeconds even when it's larger than the default
+	// GracefulTerminationSeconds ceiling, up to MaxGracePeriodSeconds, so
+	// slow-shutdown workloads like databases aren't cut short. The
+	// node-level drain timeout is scaled to match.
+	ExtendedGracePeriod   bool
+	MaxGracePeriodSeconds int64
+
+	// EvictDeletionTimeout bounds how long a drain waits, per pod, after
+	// the API server accepts an eviction, for the pod to actually
+	// disappear before giving up on that pod (see
+	// podDeletionTracker.WaitFor and ErrPodDeletionTimeout).
+	EvictDeletionTimeout time.Duration
+
+	// NodeGroupLabel, if set, groups nodes by this label's value (e.g. a
+	// cloud provider's node-group/nodepool label) and applies
+	// MinNodes/MaxNodesDelete/ReapFraction independently within each
+	// group rather than across the cluster as a whole, then merges the
+	// per-group results round-robin so the aggregate reap list draws
+	// evenly from every group instead of exhausting one before the next.
+	NodeGroupLabel string
+
+	// HonorSafeToEvict treats pods annotated
+	// cluster-autoscaler.kubernetes.io/safe-to-evict=false or
+	// karpenter.sh/do-not-disrupt=true as deal-breakers, passing over the
+	// whole node rather than evicting them.
+	HonorSafeToEvict bool
+
+	// MaxPodDensity, if positive, passes over any node hosting more
+	// evictable pods than this, since draining a very dense node risks a
+	// rescheduling storm better handled during dedicated maintenance. 0
+	// disables the gate.
+	MaxPodDensity int
+
+	// YoungPodGrace, if positive, passes over any node hosting a pod younger
+	// than this, since evicting a pod that just started churns a workload
+	// that may still be warming caches or registering with load balancers.
+	// 0 disables the gate.
+	YoungPodGrace time.Duration
+
+	// PDBFeasibilityCheck, when true, passes over any node hosting a pod
+	// covered by a structurally infeasible PodDisruptionBudget (e.g.
+	// maxUnavailable 0, or a single replica required to stay fully
+	// healthy) before cordoning it, rather than discovering the same thing
+	// mid-drain after the node is already half-evicted.
+	PDBFeasibilityCheck bool
+
+	// CapacityHeadroomCheck, when true, passes over a selected node unless
+	// the rest of the cluster's allocatable CPU and memory can absorb the
+	// requests of the pods it would displace, plus CapacityHeadroomMargin
+	// -- turning MinNodes into a real capacity guard instead of a bare
+	// node count, which a cluster of unevenly sized nodes can satisfy
+	// while still being too small to actually hold what was removed.
+	CapacityHeadroomCheck bool
+	// CapacityHeadroomMargin is a safety buffer on top of the bare
+	// requests CapacityHeadroomCheck requires the remaining nodes to
+	// absorb -- e.g. 0.1 requires 10% more free capacity than the
+	// displaced requests alone.
+	CapacityHeadroomMargin float64
+
+	// SchedulerSimulationCheck, when true, runs a lightweight bin-packing
+	// simulation against each node CapacityHeadroomCheck would otherwise
+	// approve: every pod on the candidate must have at least one
+	// remaining node it could actually be scheduled onto, given its
+	// nodeSelector, required node affinity, tolerations, and topology
+	// spread constraints, not just raw CPU/memory headroom. Off by
+	// default since it's considerably more expensive than the other
+	// eligibility checks.
+	SchedulerSimulationCheck bool
+
+	// PostHarvestVerification settings: after a node is harvested, check
+	// that a handful of cluster invariants still hold, and halt the run
+	// before starting the next node if any of them don't, since a harvest
+	// that silently broke something is worse caught immediately than
+	// discovered by an operator later.
+	PostHarvestVerification bool
+	// VerifyPendingPodGrace, if positive, fails verification when a
+	// replacement for one of the harvested node's evicted workloads is
+	// still Pending after this long. 0 disables this specific check.
+	VerifyPendingPodGrace time.Duration
+	// PrometheusAlertGateURL, if set, fails verification when a new alert
+	// (one that wasn't already firing right before the harvest) is firing
+	// against this Prometheus-compatible HTTP API immediately after it.
+	// Empty disables this specific check.
+	PrometheusAlertGateURL   string
+	PrometheusAlertGateQuery string
+
+	// ZoneBalanceMaxSkew, if positive, refuses to reap a node if doing so
+	// would leave its availability zone (topology.kubernetes.io/zone) more
+	// than this many nodes behind the fullest other zone. 0 disables the
+	// constraint.
+	ZoneBalanceMaxSkew int
+
+	// MaxHarvestFailures is how many times grim-reaper will retry
+	// harvesting a node that keeps failing before giving up on it and
+	// raising an operator-facing notification instead. 0 disables the
+	// limit (always keep retrying).
+	MaxHarvestFailures int
+
+	// GCE settings, used when CloudProvider is "gce".
+	GCEProject   string
+	GCEGroupName string
+	GCERegion    string
+	GCEZone      string
+
+	// Passover ledger settings.
+	PassoverLedgerEnabled       bool
+	PassoverLedgerTTL           time.Duration
+	PassoverEscalationThreshold int
+
+	// External disruption ledger settings. When enabled, MaxNodesDelete is
+	// reduced by the number of disruptions other tools (cluster upgrade
+	// controllers, chaos engineering tools) have recorded in the same
+	// ConfigMap-based ledger convention within ExternalDisruptionWindow, so
+	// combined node churn from every tool touching the cluster stays within
+	// one agreed limit instead of each tool budgeting independently.
+	ExternalDisruptionLedgerEnabled bool
+	ExternalDisruptionLedgerName    string
+	ExternalDisruptionWindow        time.Duration
+
+	// Azure settings, used when CloudProvider is "azure".
+	AzureResourceGroup string
+	AzureScaleSetName  string
+	AzureSubscriptionID string
+
+	// PodInformerCache, when true (the default), backs pod reads used
+	// during node selection (ListPodsOnNode, ListPendingPods, ListPods)
+	// with a single cluster-wide informer cache instead of a fresh,
+	// server-side-filtered List call every time one is asked for -- the
+	// difference between one API list and thousands of them on a cluster
+	// with thousands of nodes. Eviction itself is unaffected; it always
+	// goes straight to the API server.
+	PodInformerCache bool
+
+	// PodInformerSyncTimeout caps how long grim-reaper waits for
+	// PodInformerCache's initial cache sync before giving up and failing
+	// startup.
+	PodInformerSyncTimeout time.Duration
+
+	// HarvestPacingBase is the base delay between harvesting nodes within a
+	// run; it scales up with cluster size and scheduler backlog. Zero
+	// disables pacing entirely.
+	HarvestPacingBase time.Duration
+
+	// MaxConcurrentNodeDrains caps how many nodes Run drains at once. 1
+	// (the default) harvests strictly one node at a time, as grim-reaper
+	// always has; raising it speeds up a large run at the cost of more
+	// simultaneous load on the API server and the scheduler. Dispatching
+	// a new node still respects HarvestPacingBase and the backpressure
+	// checks below; this only bounds how many harvests run concurrently.
+	MaxConcurrentNodeDrains int
+
+	// BatchSize and BatchPause split a run into waves: grim-reaper
+	// dispatches at most BatchSize nodes (still subject to
+	// MaxConcurrentNodeDrains within the wave), waits for all of them to
+	// finish harvesting, then pauses BatchPause before dispatching the
+	// next wave. This is a coarser knob than NodeCooldown -- it's meant
+	// for large runs (e.g. MaxNodesDelete in the dozens during an AMI
+	// rotation) where the cluster needs a breather every few nodes rather
+	// than after every single one. BatchSize zero (the default) disables
+	// batching: the whole run dispatches as one wave, as it always has.
+	BatchSize  int
+	BatchPause time.Duration
+
+	// NodeCooldown is how long grim-reaper pauses after successfully
+	// harvesting a node before dispatching the next, giving the scheduler
+	// and autoscaler time to absorb the pods it displaced. Zero disables
+	// the pause. By default this is a fixed sleep; set
+	// NodeCooldownWaitForPodsRunning to poll instead.
+	NodeCooldown time.Duration
+
+	// NodeCooldownWaitForPodsRunning, when true, turns NodeCooldown into a
+	// timeout rather than a fixed sleep: grim-reaper polls (every
+	// BackpressureCheckInterval) until every pod evicted from the
+	// harvested node is Running again, or until NodeCooldown elapses,
+	// whichever comes first.
+	NodeCooldownWaitForPodsRunning bool
+
+	// ReplacementReadyTimeout, if positive, makes grim-reaper wait after
+	// harvesting a node until every pod it evicted has a Ready
+	// replacement from the same controller, or until
+	// ReplacementReadyTimeout elapses, before starting the next harvest.
+	// Zero skips the wait entirely.
+	ReplacementReadyTimeout time.Duration
+
+	// MaxConsecutiveReplacementFailures aborts the run once this many
+	// harvests in a row have timed out waiting on
+	// ReplacementReadyTimeout, on the theory that a scheduler that can't
+	// place replacements for several nodes running is unlikely to place
+	// them for the next one either. Zero never aborts on this basis,
+	// logging the timeout and continuing instead.
+	MaxConsecutiveReplacementFailures int
+
+	// MaxPendingPods and MaxPendingPodAge apply hard back-pressure between
+	// harvests: while the cluster has more than MaxPendingPods Pending
+	// pods, or the oldest Pending pod has been waiting longer than
+	// MaxPendingPodAge, grim-reaper pauses (beyond whatever HarvestPacingBase
+	// already adds) until the scheduler catches up, rechecking every
+	// BackpressureCheckInterval. Zero disables the corresponding check.
+	MaxPendingPods            int
+	MaxPendingPodAge          time.Duration
+	BackpressureCheckInterval time.Duration
+
+	// BackpressureTimeout caps how long a single pause can last before
+	// grim-reaper gives up waiting and harvests the node anyway. Zero waits
+	// indefinitely.
+	BackpressureTimeout time.Duration
+
+	// MaxUnschedulablePods is a circuit breaker, checked before a run
+	// starts and again before each subsequent node: while the cluster has
+	// more than this many pods the scheduler has marked Unschedulable,
+	// removing more capacity would only worsen the pressure. By default
+	// this pauses and rechecks like the MaxPendingPods back-pressure
+	// check above (same BackpressureCheckInterval/BackpressureTimeout);
+	// set UnschedulablePodsAbort to stop the run outright instead. Zero
+	// disables the check.
+	MaxUnschedulablePods int
+
+	// UnschedulablePodsAbort, when true, makes the MaxUnschedulablePods
+	// circuit breaker abort the run immediately instead of pausing and
+	// rechecking.
+	UnschedulablePodsAbort bool
+
+	// ObserverMode runs the full selection and dry-run-eviction pipeline on
+	// every cycle, publishing what-would-happen metrics, without ever
+	// cordoning, draining, or deleting anything.
+	ObserverMode bool
+
+	// DrainBackend selects which implementation evicts pods from a node:
+	// "custom" (grim-reaper's own evictor, default) or "kubectl" (the
+	// k8s.io/kubectl/pkg/drain helper, for kubectl-identical semantics).
+	DrainBackend string
+
+	// BarePodPolicy controls how pods with no controller owner are treated
+	// during a drain, since evicting one destroys it permanently instead of
+	// letting a controller reschedule it. Supported values: "skip",
+	// "evict", "passover-node", "require-approval".
+	BarePodPolicy string
+
+	// PushgatewayURL, if set, receives this run's metrics at the end of the
+	// run via a Prometheus Pushgateway, since grim-reaper's process usually
+	// exits (or sleeps) before the next scrape would catch them.
+	PushgatewayURL     string
+	PushgatewayJobName string
+
+	// Mode selects whether grim-reaper exits after a single cycle ("run")
+	// or keeps leading and re-evaluating the cluster on RunInterval
+	// ("daemon").
+	Mode string
+
+	// OutputFormat selects how the `plan` subcommand renders its preview:
+	// a human-readable table, or machine-readable json/yaml.
+	OutputFormat string
+
+	// RunIntervalJitter adds up to this much random jitter to each daemon
+	// cycle's sleep, so a fleet of reapers (or HA replicas) don't all wake
+	// and hit the API server in lockstep.
+	RunIntervalJitter time.Duration
+
+	// GenerateDashboardsDir, if set, writes a Grafana dashboard and
+	// PrometheusRule alerts generated from grim-reaper's metric
+	// definitions into this directory, then exits without running.
+	GenerateDashboardsDir string
+
+	// PrintConfig, if set, prints the fully resolved (flags/env/file
+	// merged), secret-redacted configuration as JSON and exits without
+	// running.
+	PrintConfig bool
+
+	// ConfigzAddr, if set, serves the same redacted configuration
+	// PrintConfig prints over HTTP at /configz on this address (e.g.
+	// ":9103"), so a running daemon's effective configuration can be
+	// checked without restarting it.
+	ConfigzAddr string
+
+	// ReportFile, if set, writes a JSON reaper.RunReport (nodes reaped,
+	// passed over with reasons, per-node durations, evicted pod counts,
+	// errors) after every cycle not run in observer mode to this path, or
+	// to stdout if set to
+	// "-", for consumption by CI pipelines and audit tooling. Empty
+	// disables report output.
+	ReportFile string
+
+	// Schedule, if set, is a standard 5-field cron expression defining
+	// approved maintenance windows; cycles outside the window (extended by
+	// MaintenanceWindowDuration) are skipped.
+	Schedule                 string
+	MaintenanceWindowDuration time.Duration
+
+	// ConfigFile, if set, loads additional settings from a YAML file.
+	// Flags explicitly passed on the command line always take precedence
+	// over values in the file.
+	ConfigFile string
+
+	// SoakDuration, if non-zero, keeps a drained node cordoned but alive
+	// for this long before it is deleted and its instance terminated, so a
+	// problem surfacing after the workloads moved can still be rolled back
+	// with a plain uncordon.
+	SoakDuration time.Duration
+
+	// SelfNodeName is the node grim-reaper's own pod is running on. That
+	// node is placed last in the selection strategy's ordering, so a run
+	// doesn't kill itself mid-harvest; forced-reap criteria and the
+	// preference for already-free nodes are applied after that and can
+	// still select it ahead of other nodes. It defaults to the NODE_NAME
+	// downward-API environment variable set by the standard Deployment
+	// manifest. If left empty and PodName/PodNamespace are set instead,
+	// grim-reaper resolves it itself by looking up its own pod.
+	SelfNodeName string
+
+	// PodName and PodNamespace identify grim-reaper's own pod, via the
+	// POD_NAME/POD_NAMESPACE downward-API environment variables. They're
+	// only consulted to resolve SelfNodeName when it isn't already set, so
+	// self-preservation keeps working even on a manifest that forgot to
+	// wire up NODE_NAME directly.
+	PodName      string
+	PodNamespace string
+
+	// MaxNodeAge, if non-zero, selects any node older than this TTL for
+	// reaping regardless of ReapFraction, enforcing a node-recycling
+	// policy. Selection is still bounded by MinNodes/MaxNodesDelete.
+	MaxNodeAge time.Duration
+
+	// FakeCluster, if set, runs the whole pipeline against an in-memory
+	// fake clientset instead of a real one, ignoring Kubeconfig entirely.
+	// Accepts a synthetic spec ("nodes=50,pods-per-node=30"), a recorded
+	// scenario ("scenario=pdb-blocked"), or a `kubectl cluster-info dump`
+	// capture ("dump=/path/to/dump.json"); see fakecluster.Load.
+	FakeCluster string
+
+	// AttachHarvestTranscripts includes the ordered, timestamped list of
+	// actions taken on a node in its failure notification, so a responder
+	// can see exactly what happened without digging through logs.
+	AttachHarvestTranscripts bool
+
+	// ScoreWeight* configure the "weighted" strategy: each node's reap
+	// score is the weighted sum of its age (hours), pod count, total pod
+	// restart count, whether it's already cordoned, and the value of its
+	// grim-reaper.io/score annotation. Nodes are reaped highest-score
+	// first.
+	ScoreWeightAge        float64
+	ScoreWeightPodCount   float64
+	ScoreWeightRestarts   float64
+	ScoreWeightCordoned   float64
+	ScoreWeightAnnotation float64
+
+	// TargetKubeletVersion and TargetImageRegex, if set, force-reap any
+	// node whose kubelet version doesn't exactly match, or whose node
+	// image (node.Status.NodeInfo.OSImage) doesn't match the regex,
+	// regardless of ReapFraction, driving a rolling node upgrade.
+	TargetKubeletVersion string
+	TargetImageRegex     string
+
+	// NotReadyGrace, if non-zero, reaps any node whose Ready condition has
+	// been False/Unknown for longer than this, as a node auto-repair
+	// measure. Selection is capped by NotReadyMaxNodesDelete, a budget kept
+	// separate from MaxNodesDelete/ReapFraction, though MinNodes is still
+	// respected across both budgets combined.
+	NotReadyGrace          time.Duration
+	NotReadyMaxNodesDelete int
+
+	// FeatureGates holds the resolved state of every named gate passed via
+	// --feature-gates, keyed by gate name. Gates not present in the map are
+	// considered disabled; use FeatureEnabled rather than indexing this map
+	// directly. Lets a risky new subsystem ship disabled by default and be
+	// turned on per cluster without a flag of its own.
+	FeatureGates map[string]bool
+
+	// AllowedTenantTiers lists the values of NamespaceTierAnnotation this
+	// policy is explicitly allowed to evict pods from. A namespace
+	// annotated with a tier not on this list causes its node to be passed
+	// over entirely, so a misconfigured cluster-wide reaper can't disrupt
+	// a tenant it was never authorized to touch. Namespaces with no tier
+	// annotation are unaffected.
+	AllowedTenantTiers []string
+
+	// RollbackOnShutdown, if set, reverts every node still marked for
+	// destruction but not yet harvested when the process receives
+	// SIGTERM or SIGINT, as if an operator had run `grim-reaper uncordon`.
+	// The node grim-reaper is actively draining when the signal arrives is
+	// left alone; only nodes it hasn't started harvesting yet are rolled
+	// back.
+	RollbackOnShutdown bool
+
+	// WarmupScanEnabled runs a read-only inventory scan (see
+	// GrimReaper.Warmup) before the first real cycle, so a freshly
+	// deployed reaper logs what it found -- in-progress marks, stale
+	// markers, orphaned cordons -- before ever acting on it.
+	WarmupScanEnabled bool
+
+	// WarmupScanQPS caps how many nodes per second the startup warm-up
+	// scan inspects, so it doesn't itself spike API server load
+	// alongside everything else starting up in a fresh deploy. 0
+	// disables pacing.
+	WarmupScanQPS float64
+
+	// StatefulReplicationRateMBPerSec is the cluster's storage layer's
+	// typical re-replication/re-attachment throughput. When set alongside
+	// GrimReaper.PVCs, each harvested node's PVC-backed pods add an extra
+	// pacing delay before the next harvest, sized to let that much data
+	// catch up. 0 disables the extra pacing.
+	StatefulReplicationRateMBPerSec float64
+
+	// MaxStatefulGBPerRun caps how much PVC-backed storage a single run
+	// will displace across all its harvested nodes. Once a node would push
+	// the running total over the cap, it and every node after it are left
+	// marked for the next run to resume. 0 means no cap.
+	MaxStatefulGBPerRun float64
+
+	// MaxConcurrentEvictions caps how many pods on a single node are
+	// evicted at once. 1 (the default) evicts one at a time, as
+	// grim-reaper always has; raising it speeds up draining dense nodes
+	// at the cost of more concurrent load on the API server and more of
+	// the reaper's own memory held at once.
+	MaxConcurrentEvictions int
+
+	// EvictionRateLimit caps how many eviction calls, across every pod on
+	// the node, are issued per second, independent of
+	// MaxConcurrentEvictions -- a high concurrency cap still lets a very
+	// dense node's batch stampede the API server and its PDBs in a single
+	// burst, which a concurrency cap alone doesn't prevent. 0 disables the
+	// limit.
+	EvictionRateLimit float64
+
+	// MaxMemoryMB, if set, is a self-imposed ceiling on the reaper
+	// process's own memory use. As usage approaches it, eviction
+	// concurrency is degraded toward serial (MaxConcurrentEvictions=1)
+	// rather than letting a very dense node's eviction batch push the
+	// process into an OOM kill. 0 disables the check.
+	MaxMemoryMB int
+
+	// CollectEventCorrelation, if set, collects the Kubernetes Events
+	// involving a harvested node and its evicted pods (e.g.
+	// FailedScheduling, Unhealthy) and attaches a deduplicated summary to
+	// that node's entry in the run report, so a responder doing post-reap
+	// impact analysis doesn't have to go dig through `kubectl get events`
+	// themselves.
+	CollectEventCorrelation bool
+
+	// Profile selects a named preset ("conservative", "balanced", or
+	// "aggressive") that bundles default budgets, timeouts, and gates
+	// appropriate to how disruptive a run is allowed to be, so a new user
+	// doesn't have to individually tune every flag to get a sane starting
+	// point. Applied after flag defaults but before env vars and
+	// --config, so any of those can still override an individual setting.
+	// Empty leaves every flag at its ordinary hardcoded default. See
+	// applyProfile.
+	Profile string
+}
+
+// New parses process flags into a Config, then layers in a `--config` YAML
+// file if one was given, and validates the result. It exits the process via
+// log.Fatal on any validation error, matching how the flag package itself
+// reports bad flag values.
+func New() *Config {
+	c, err := load()
+	if err != nil {
+		log.Fatalf("loading config: %v", err)
+	}
+	return c
+}
+
+// load is the error-returning implementation behind New, kept separate so
+// it can be unit tested without exercising log.Fatal.
+func load() (*Config, error) {
+	c := &Config{}
+	flag.StringVar(&c.Kubeconfig, "kubeconfig", "", "path to a kubeconfig file; empty uses in-cluster config")
+	flag.StringVar(&c.NodeSelector, "node-selector", "", "label selector restricting which nodes are eligible for reaping")
+	flag.StringVar(&c.Strategy, "strategy", "oldest", "node selection strategy: oldest, newest, random, emptiest, fragmentation, most-restarts, or weighted")
+	flag.Float64Var(&c.ReapFraction, "reap-fraction", 0.1, "fraction of eligible nodes to reap per run")
+	flag.StringVar(&c.MinNodes, "min-nodes", "3", "never reap if doing so would drop the cluster below this many nodes; accepts an absolute count or a percentage (e.g. \"80%\") of the current fleet size")
+	flag.BoolVar(&c.MinNodesRequireReady, "min-nodes-require-ready", true, "count only Ready nodes toward --min-nodes, re-checked before each harvest")
+	flag.IntVar(&c.MaxNodesDelete, "max-nodes-delete", 5, "maximum number of nodes reaped in a single run")
+	flag.StringVar(&c.MaxUnavailable, "max-unavailable", "", "PodDisruptionBudget-style budget on top of --max-nodes-delete: an absolute count or a percentage (e.g. \"10%\") of the selected node pool, counting nodes already cordoned by other actors; empty disables the check")
+	flag.BoolVar(&c.DryRun, "dry-run", false, "compute and log the reap plan without taking any action")
+	flag.BoolVar(&c.Canary, "canary", false, "harvest exactly one node from a multi-node batch, verify it was absorbed cleanly, and only then proceed with the rest")
+	flag.DurationVar(&c.CanaryVerificationPeriod, "canary-verification-period", 5*time.Minute, "how long --canary watches the cluster after the canary harvest before proceeding; 0 disables the wait")
+	flag.BoolVar(&c.LeaderElection, "leader-election", true, "only reap while holding the leader election lock")
+	flag.StringVar(&c.LeaseNamespace, "leader-election-namespace", "kube-system", "namespace holding the leader election lock")
+	flag.StringVar(&c.StatusAddr, "status-addr", "", "serve /metrics, /healthz, /readyz, /status (leader or standby), and /plan over HTTP on this address; empty disables it")
+	flag.DurationVar(&c.StaleMarkerTTL, "stale-marker-ttl", 0, "uncordon and clear the marker on a node marked for destruction but never harvested after this long, instead of resuming it forever; 0 disables the GC")
+	flag.StringVar(&c.ReapTaint, "reap-taint", "", "apply this NoSchedule taint (key=value, e.g. grim-reaper.io/reaping=true, or grim-reaper.io/reaping=$RUNID to tag it with the run ID) in addition to cordoning; empty disables it")
+	flag.StringVar(&c.LeaseName, "leader-election-lock-name", "grim-reaper", "name of the leader election lock object")
+	flag.StringVar(&c.LockType, "lock-type", "leases", "leader election lock backend (leases is the only supported value)")
+	flag.DurationVar(&c.RunInterval, "run-interval", 60*time.Minute, "how long to sleep between runs")
+	flag.StringVar(&c.PlatformWebhookURL, "platform-webhook-url", "", "webhook notified about every eviction, regardless of namespace")
+	namespaceWebhooks := flag.String("namespace-webhooks", "", "comma-separated namespace=webhookURL pairs for per-team eviction notifications")
+	flag.StringVar(&c.CloudProvider, "cloud-provider", "none", "cloud integration used to terminate instances after a node is reaped: none, aws, gce, or azure")
+	flag.DurationVar(&c.CloudTerminationTimeout, "cloud-termination-timeout", 5*time.Minute, "how long to wait for the cloud provider to confirm instance termination")
+	flag.StringVar(&c.InstanceDeletionMode, "instance-deletion-mode", "shrink", "how terminating an instance affects its group's capacity: shrink (decrement desired/target size), recycle (leave it alone so the group replaces the instance), or detach-terminate (detach then terminate directly); not every cloud provider supports every mode")
+	flag.BoolVar(&c.AsyncTermination, "async-termination", false, "hand instance termination off to a background queue instead of blocking the next node's drain on it; failures are retried independently")
+	flag.IntVar(&c.TerminationMaxRetries, "termination-max-retries", 0, "maximum termination retries per instance when --async-termination is set; 0 means unlimited")
+	flag.DurationVar(&c.PreAnnounceDuration, "pre-announce-duration", 0, "if set, notify affected namespaces and wait this long after cordoning a node before draining it")
+	flag.StringVar(&c.EvictionBackoffPolicy, "eviction-backoff-policy", "jittered-exponential", "retry backoff for rejected evictions: constant, exponential, decorrelated-jitter, or jittered-exponential; overridden per-attempt by a server Retry-After when present")
+	flag.DurationVar(&c.EvictionBackoffBase, "eviction-backoff-base", 5*time.Second, "base delay for the eviction backoff policy")
+	flag.DurationVar(&c.EvictionBackoffMax, "eviction-backoff-max", time.Minute, "maximum delay for the eviction backoff policy")
+	flag.IntVar(&c.EvictionMaxRetries, "eviction-max-retries", 12, "maximum number of times a rejected eviction is retried")
+	flag.BoolVar(&c.ForceDeleteAfterTimeout, "force-delete-after-timeout", false, "once a pod's eviction retries are exhausted, delete it directly instead of failing the node's drain")
+	flag.DurationVar(&c.ForceDeleteGracePeriod, "force-delete-grace-period", 0, "grace period given to a pod that's force-deleted after --force-delete-after-timeout kicks in")
+	flag.BoolVar(&c.ExtendedGracePeriod, "extended-grace-period", false, "honor a pod's own terminationGracePeriodSeconds when it's larger than the default, up to --max-grace-period-seconds")
+	flag.Int64Var(&c.MaxGracePeriodSeconds, "max-grace-period-seconds", 600, "ceiling grace period granted to a pod when --extended-grace-period is set")
+	flag.DurationVar(&c.EvictDeletionTimeout, "evict-deletion-timeout", 2*time.Minute, "how long a drain waits, per pod, for an accepted eviction to actually delete the pod before giving up on it")
+	flag.IntVar(&c.MaxHarvestFailures, "max-harvest-failures", 3, "give up retrying a node after this many failed harvest attempts and notify an operator instead; 0 disables the limit")
+	flag.StringVar(&c.NodeGroupLabel, "node-group-label", "", "label key used to group nodes; when set, min-nodes/max-nodes-delete/reap-fraction apply per group and results are merged round-robin across groups")
+	flag.BoolVar(&c.HonorSafeToEvict, "honor-safe-to-evict", true, "pass over a node if any of its pods are annotated cluster-autoscaler.kubernetes.io/safe-to-evict=false or karpenter.sh/do-not-disrupt=true")
+	flag.IntVar(&c.MaxPodDensity, "max-pod-density", 0, "pass over a node hosting more than this many evictable pods; 0 disables the gate")
+	flag.DurationVar(&c.YoungPodGrace, "young-pod-grace", 0, "pass over a node hosting a pod younger than this; 0 disables the gate")
+	flag.BoolVar(&c.PDBFeasibilityCheck, "pdb-feasibility-check", true, "pass over a node hosting a pod covered by a structurally infeasible PodDisruptionBudget instead of timing out mid-drain")
+	flag.BoolVar(&c.CapacityHeadroomCheck, "capacity-headroom-check", true, "pass over a selected node unless the rest of the cluster has enough allocatable headroom to absorb the pods it would displace")
+	flag.Float64Var(&c.CapacityHeadroomMargin, "capacity-headroom-margin", 0.1, "safety margin required on top of displaced pods' requests by --capacity-headroom-check, e.g. 0.1 requires 10% more free capacity than the requests alone")
+	flag.BoolVar(&c.SchedulerSimulationCheck, "scheduler-simulation-check", false, "simulate scheduling (nodeSelector, required node affinity, tolerations, topology spread) for every pod on a candidate node before approving it, not just raw resource headroom")
+	flag.BoolVar(&c.PostHarvestVerification, "post-harvest-verification", true, "after harvesting a node, verify min-nodes, pending-pod, and alert-gate invariants before continuing to the next node")
+	flag.DurationVar(&c.VerifyPendingPodGrace, "verify-pending-pod-grace", 2*time.Minute, "fail post-harvest verification if a harvested node's evicted workload is still Pending after this long; 0 disables this check")
+	flag.StringVar(&c.PrometheusAlertGateURL, "prometheus-alert-gate-url", "", "Prometheus-compatible HTTP API used by post-harvest verification to detect newly firing alerts; empty disables this check")
+	flag.StringVar(&c.PrometheusAlertGateQuery, "prometheus-alert-gate-query", `ALERTS{alertstate="firing"}`, "query run against prometheus-alert-gate-url to list firing alerts")
+	flag.IntVar(&c.ZoneBalanceMaxSkew, "zone-balance-max-skew", 0, "refuse to reap a node if it would leave its zone more than this many nodes behind other zones; 0 disables the constraint")
+	flag.StringVar(&c.GCEProject, "gce-project", "", "GCE project containing the managed instance group (cloud-provider=gce)")
+	flag.StringVar(&c.GCEGroupName, "gce-instance-group", "", "name of the GCE managed instance group (cloud-provider=gce)")
+	flag.StringVar(&c.GCERegion, "gce-region", "", "region of the GCE managed instance group, for regional MIGs")
+	flag.StringVar(&c.GCEZone, "gce-zone", "", "zone of the GCE managed instance group, for zonal MIGs")
+	flag.BoolVar(&c.PassoverLedgerEnabled, "passover-ledger-enabled", true, "persist passed-over nodes so repeated passovers can be escalated")
+	flag.DurationVar(&c.PassoverLedgerTTL, "passover-ledger-ttl", 24*time.Hour, "how long a passover entry counts toward escalation")
+	flag.IntVar(&c.PassoverEscalationThreshold, "passover-escalation-threshold", 5, "number of passovers for the same reason before escalating")
+	flag.BoolVar(&c.ExternalDisruptionLedgerEnabled, "external-disruption-ledger-enabled", false, "reduce max-nodes-delete by disruptions other tools have recorded in an external ledger ConfigMap")
+	flag.StringVar(&c.ExternalDisruptionLedgerName, "external-disruption-ledger-name", "node-disruption-ledger", "name of the ConfigMap, in --leader-election-namespace, other tools record disruptions into")
+	flag.DurationVar(&c.ExternalDisruptionWindow, "external-disruption-window", time.Hour, "how far back to count entries in the external disruption ledger")
+	flag.StringVar(&c.AzureResourceGroup, "azure-resource-group", "", "resource group containing the VMSS (cloud-provider=azure)")
+	flag.StringVar(&c.AzureScaleSetName, "azure-scale-set", "", "name of the Virtual Machine Scale Set (cloud-provider=azure)")
+	flag.StringVar(&c.AzureSubscriptionID, "azure-subscription-id", "", "Azure subscription ID (cloud-provider=azure)")
+	flag.BoolVar(&c.PodInformerCache, "pod-informer-cache", true, "serve pod reads used during node selection from a cluster-wide informer cache instead of a fresh API list call per node")
+	flag.DurationVar(&c.PodInformerSyncTimeout, "pod-informer-sync-timeout", 30*time.Second, "how long to wait for --pod-informer-cache's initial cache sync before giving up")
+	flag.DurationVar(&c.HarvestPacingBase, "harvest-pacing-base", 10*time.Second, "base delay between harvesting nodes, scaled by cluster size and scheduler backlog; 0 disables pacing")
+	flag.IntVar(&c.MaxConcurrentNodeDrains, "max-concurrent-node-drains", 1, "maximum number of nodes drained at once; 1 harvests strictly one node at a time")
+	flag.IntVar(&c.BatchSize, "batch-size", 0, "harvest nodes in waves of this many, pausing --batch-pause between waves; 0 harvests the whole run as a single wave")
+	flag.DurationVar(&c.BatchPause, "batch-pause", 0, "pause between waves when --batch-size is set; 0 disables the pause")
+	flag.DurationVar(&c.NodeCooldown, "node-cooldown", 0, "pause after harvesting a node before starting the next; 0 disables the pause")
+	flag.BoolVar(&c.NodeCooldownWaitForPodsRunning, "node-cooldown-wait-for-pods-running", false, "treat --node-cooldown as a timeout and poll until the harvested node's evicted pods are Running again, instead of sleeping for the full duration")
+	flag.DurationVar(&c.ReplacementReadyTimeout, "replacement-ready-timeout", 0, "wait after a harvest until evicted pods' replacements are Ready, up to this long; 0 skips the wait")
+	flag.IntVar(&c.MaxConsecutiveReplacementFailures, "max-consecutive-replacement-failures", 0, "abort the run after this many harvests in a row whose replacements failed to become Ready within --replacement-ready-timeout; 0 never aborts on this basis")
+	flag.IntVar(&c.MaxPendingPods, "max-pending-pods", 0, "pause between harvests while the cluster has more than this many Pending pods; 0 disables this check")
+	flag.DurationVar(&c.MaxPendingPodAge, "max-pending-pod-age", 0, "pause between harvests while the oldest Pending pod has been waiting longer than this; 0 disables this check")
+	flag.DurationVar(&c.BackpressureCheckInterval, "backpressure-check-interval", 15*time.Second, "how often to recheck Pending pods while paused for back-pressure")
+	flag.DurationVar(&c.BackpressureTimeout, "backpressure-timeout", 0, "give up waiting for back-pressure to clear and harvest anyway after this long; 0 waits indefinitely")
+	flag.IntVar(&c.MaxUnschedulablePods, "max-unschedulable-pods", 0, "pending-pods circuit breaker: pause (or abort, see --unschedulable-pods-abort) once more than this many pods are Unschedulable; 0 disables the check")
+	flag.BoolVar(&c.UnschedulablePodsAbort, "unschedulable-pods-abort", false, "abort the run immediately when --max-unschedulable-pods is exceeded, instead of pausing and rechecking")
+	flag.BoolVar(&c.ObserverMode, "observer-mode", false, "run the full pipeline as dry-run evictions and publish what-would-happen metrics, never mutating the cluster")
+	flag.StringVar(&c.DrainBackend, "drain-backend", "custom", "pod eviction backend: custom or kubectl")
+	flag.StringVar(&c.BarePodPolicy, "bare-pod-policy", "passover-node", "how to treat pods with no controller owner during a drain: skip, evict, passover-node, or require-approval")
+	flag.StringVar(&c.PushgatewayURL, "pushgateway-url", "", "if set, push this run's metrics to this Prometheus Pushgateway URL")
+	flag.StringVar(&c.PushgatewayJobName, "pushgateway-job-name", "grim-reaper", "job name used when pushing metrics to the Pushgateway")
+	flag.StringVar(&c.Mode, "mode", "run", "run: exit after one cycle. daemon: keep leading and re-evaluate every --run-interval")
+	flag.StringVar(&c.OutputFormat, "output", "table", "output format for the `plan` subcommand: table, json, or yaml")
+	flag.DurationVar(&c.RunIntervalJitter, "run-interval-jitter", time.Minute, "maximum random jitter added to each daemon cycle's sleep")
+	flag.StringVar(&c.GenerateDashboardsDir, "generate-dashboards", "", "write a Grafana dashboard and PrometheusRule alerts to this directory and exit")
+	flag.BoolVar(&c.PrintConfig, "print-config", false, "print the fully resolved, secret-redacted configuration as JSON and exit")
+	flag.StringVar(&c.ConfigzAddr, "configz-addr", "", "serve the resolved, secret-redacted configuration over HTTP at /configz on this address; empty disables it")
+	flag.StringVar(&c.ReportFile, "report-file", "", "write a JSON report of each run (nodes reaped, passed over, durations, evicted pod counts, errors) to this path, or \"-\" for stdout; empty disables it")
+	flag.StringVar(&c.Schedule, "schedule", "", "standard 5-field cron expression; cycles outside the resulting maintenance window are skipped")
+	flag.DurationVar(&c.MaintenanceWindowDuration, "maintenance-window-duration", time.Hour, "how long each maintenance window stays open after a --schedule match")
+	flag.StringVar(&c.ConfigFile, "config", "", "optional YAML file of settings; flags explicitly passed on the command line override it")
+	flag.DurationVar(&c.SoakDuration, "soak-duration", 0, "keep a drained node cordoned but alive this long before deleting it, so it can still be rolled back with an uncordon; 0 disables soaking")
+	flag.StringVar(&c.SelfNodeName, "self-node-name", os.Getenv("NODE_NAME"), "node grim-reaper's own pod is running on; placed last in the selection strategy's ordering, though forced-reap criteria and the free-node preference can still select it earlier")
+	flag.StringVar(&c.PodName, "pod-name", os.Getenv("POD_NAME"), "grim-reaper's own pod name; used to resolve --self-node-name by looking up the pod if it isn't set directly")
+	flag.StringVar(&c.PodNamespace, "pod-namespace", os.Getenv("POD_NAMESPACE"), "namespace of grim-reaper's own pod; used alongside --pod-name to resolve --self-node-name")
+	flag.DurationVar(&c.MaxNodeAge, "max-node-age", 0, "reap any node older than this TTL regardless of --reap-fraction, bounded by --min-nodes/--max-nodes-delete; 0 disables the TTL strategy")
+	flag.StringVar(&c.FakeCluster, "fake-cluster", "", "run against an in-memory fake cluster instead of a real one: \"nodes=50,pods-per-node=30\", \"scenario=pdb-blocked\", or \"dump=/path/to/cluster-info-dump.json\"; ignores --kubeconfig")
+	flag.BoolVar(&c.AttachHarvestTranscripts, "attach-harvest-transcripts", true, "attach the ordered, timestamped harvest transcript to failure notifications")
+	flag.Float64Var(&c.ScoreWeightAge, "score-weight-age", 1, "weight on node age in hours for the weighted strategy")
+	flag.Float64Var(&c.ScoreWeightPodCount, "score-weight-pod-count", 0, "weight on pod count for the weighted strategy")
+	flag.Float64Var(&c.ScoreWeightRestarts, "score-weight-restarts", 0, "weight on total pod restart count for the weighted strategy")
+	flag.Float64Var(&c.ScoreWeightCordoned, "score-weight-cordoned", 0, "weight on whether the node is already cordoned for the weighted strategy")
+	flag.Float64Var(&c.ScoreWeightAnnotation, "score-weight-annotation", 0, "weight on the grim-reaper.io/score annotation for the weighted strategy")
+	flag.StringVar(&c.TargetKubeletVersion, "target-kubelet-version", "", "force-reap any node whose kubelet version doesn't exactly match this, regardless of --reap-fraction, driving a rolling upgrade")
+	flag.StringVar(&c.TargetImageRegex, "target-image-regex", "", "force-reap any node whose node image doesn't match this regex, regardless of --reap-fraction, driving a rolling upgrade")
+	flag.DurationVar(&c.NotReadyGrace, "not-ready-grace", 0, "reap any node whose Ready condition has been False/Unknown this long, as node auto-repair; 0 disables the strategy")
+	flag.IntVar(&c.NotReadyMaxNodesDelete, "not-ready-max-nodes-delete", 1, "maximum number of NotReady nodes reaped in a single run, budgeted separately from --max-nodes-delete")
+	featureGates := flag.String("feature-gates", "", "comma-separated Gate=true/false pairs enabling or disabling risky subsystems, e.g. CapacityCheck=true,CloudTerminate=false; unlisted gates default to false")
+	allowedTenantTiers := flag.String("allowed-tenant-tiers", "", "comma-separated list of namespace tier annotation (grim-reaper.io/tier) values this policy may evict pods from; a namespace annotated with a tier not on this list passes over its node")
+	flag.BoolVar(&c.RollbackOnShutdown, "rollback-on-shutdown", false, "on SIGTERM/SIGINT, uncordon and clear markers on every node marked for destruction but not yet harvested, rather than leaving them for the next run to resume")
+	flag.BoolVar(&c.WarmupScanEnabled, "warmup-scan", true, "on startup, scan and log the cluster's current state (marked nodes, stale markers, orphaned cordons) before the first real cycle")
+	flag.Float64Var(&c.WarmupScanQPS, "warmup-scan-qps", 20, "maximum nodes per second inspected by the startup warm-up scan; 0 disables pacing")
+	flag.Float64Var(&c.StatefulReplicationRateMBPerSec, "stateful-replication-rate-mb-per-sec", 0, "cluster storage layer's typical re-replication/re-attachment throughput in MB/sec; when set, an extra pacing delay is added after harvesting a node with PVC-backed pods; 0 disables the extra pacing")
+	flag.Float64Var(&c.MaxStatefulGBPerRun, "max-stateful-gb-per-run", 0, "cap, in GB, on how much PVC-backed storage a single run will displace across all harvested nodes; 0 means no cap")
+	flag.IntVar(&c.MaxConcurrentEvictions, "max-concurrent-evictions", 1, "maximum number of pods on a single node evicted at once; 1 evicts one at a time")
+	flag.Float64Var(&c.EvictionRateLimit, "eviction-rate-limit", 0, "maximum eviction calls issued per second for a single node's drain; 0 disables the limit")
+	flag.IntVar(&c.MaxMemoryMB, "max-memory-mb", 0, "self-imposed ceiling on the reaper process's own memory use; eviction concurrency is degraded toward serial as usage approaches it; 0 disables the check")
+	flag.BoolVar(&c.CollectEventCorrelation, "collect-event-correlation", false, "collect Kubernetes Events involving a harvested node and its evicted pods and attach a deduplicated summary to the run report")
+	flag.StringVar(&c.Profile, "profile", "", "named preset (conservative, balanced, aggressive) bundling default budgets, timeouts, and gates; empty keeps every flag's ordinary default; explicit flags, env vars, and --config still override it")
+	flag.Parse()
+
+	explicitFlags := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { explicitFlags[f.Name] = true })
+
+	if err := applyProfile(c, explicitFlags); err != nil {
+		return nil, err
+	}
+
+	// Precedence is flags > env > config file: applyEnvOverrides only
+	// touches flags not already set explicitly, and marks any flag it does
+	// set so the config file merge below leaves it alone too.
+	if err := applyEnvOverrides(explicitFlags); err != nil {
+		return nil, err
+	}
+	c.NamespaceWebhooks = parseNamespaceWebhooks(*namespaceWebhooks)
+	gates, err := parseFeatureGates(*featureGates)
+	if err != nil {
+		return nil, err
+	}
+	c.FeatureGates = gates
+	c.AllowedTenantTiers = parseCommaList(*allowedTenantTiers)
+
+	if c.ConfigFile != "" {
+		fc, err := loadConfigFile(c.ConfigFile)
+		if err != nil {
+			return nil, err
+		}
+		mergeConfigFile(c, fc, explicitFlags)
+	}
+
+	if err := Validate(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// redactedPlaceholder replaces secret-bearing fields in Redacted's output.
+const redactedPlaceholder = "[REDACTED]"
+
+// Redacted returns a shallow copy of c with secret-bearing fields (webhook
+// URLs, which may embed tokens) replaced by redactedPlaceholder, so the
+// result is safe to print or serve from an unauthenticated endpoint like
+// /configz.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	if redacted.PlatformWebhookURL != "" {
+		redacted.PlatformWebhookURL = redactedPlaceholder
+	}
+	if len(redacted.NamespaceWebhooks) > 0 {
+		scrubbed := make(map[string]string, len(redacted.NamespaceWebhooks))
+		for ns := range redacted.NamespaceWebhooks {
+			scrubbed[ns] = redactedPlaceholder
+		}
+		redacted.NamespaceWebhooks = scrubbed
+	}
+	return &redacted
+}
+
+// parseNamespaceWebhooks parses a "ns1=url1,ns2=url2" flag value into a map.
+func parseNamespaceWebhooks(raw string) map[string]string {
+	webhooks := map[string]string{}
+	if raw == "" {
+		return webhooks
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		ns, url, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		webhooks[ns] = url
+	}
+	return webhooks
+}
+
+// parseCommaList splits a comma-separated flag value into its trimmed,
+// non-empty elements.
+func parseCommaList(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseFeatureGates parses a "Gate1=true,Gate2=false" flag value into a map
+// of gate name to enabled state.
+func parseFeatureGates(raw string) (map[string]bool, error) {
+	gates := map[string]bool{}
+	if raw == "" {
+		return gates, nil
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid feature gate %q: expected Name=true/false", pair)
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid feature gate %q: %w", pair, err)
+		}
+		gates[name] = enabled
+	}
+	return gates, nil
+}
+
+// FeatureEnabled reports whether the named gate is enabled in gates. A gate
+// absent from the map is disabled, so new gates can ship off by default.
+func FeatureEnabled(gates map[string]bool, name string) bool {
+	return gates[name]
+}
+
+// ParseReapTaint parses a "key=value" ReapTaint flag value into its key and
+// value.
+func ParseReapTaint(raw string) (key, value string, err error) {
+	key, value, ok := strings.Cut(raw, "=")
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid reap taint %q: expected key=value", raw)
+	}
+	return key, value, nil
+}
+
+// parsePercentOrCount parses a flag value that's either a bare non-negative
+// integer or a percentage ("10%") into an absolute count against a pool of
+// size total, the same two forms PodDisruptionBudget.Spec.MaxUnavailable
+// accepts. A percentage rounds up: "10% of 3" budgets 1, not 0. field names
+// the flag in error messages.
+func parsePercentOrCount(field, raw string, total int) (int, error) {
+	if pct, ok := strings.CutSuffix(raw, "%"); ok {
+		value, err := strconv.Atoi(pct)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %w", field, raw, err)
+		}
+		if value < 0 || value > 100 {
+			return 0, fmt.Errorf("invalid %s %q: percentage must be between 0 and 100", field, raw)
+		}
+		return int(math.Ceil(float64(value) * float64(total) / 100)), nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: expected an integer or a percentage", field, raw)
+	}
+	if value < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must not be negative", field, raw)
+	}
+	return value, nil
+}
+
+// ParseMaxUnavailable parses a MaxUnavailable flag value into an absolute
+// node count against a pool of size total. An empty raw disables the
+// budget and always returns 0.
+func ParseMaxUnavailable(raw string, total int) (int, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	return parsePercentOrCount("max-unavailable", raw, total)
+}
+
+// ParseMinNodes parses a MinNodes flag value -- either a bare integer or a
+// percentage ("80%") -- into an absolute floor against the current fleet
+// size total, so the same config works unchanged across clusters of
+// different sizes instead of needing a retuned absolute number on each one.
+func ParseMinNodes(raw string, total int) (int, error) {
+	return parsePercentOrCount("min-nodes", raw, total)
+}
@@ -0,0 +1,76 @@
+package config
+
+import "testing"
+
+func TestParseMaxUnavailable(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		total   int
+		want    int
+		wantErr bool
+	}{
+		{name: "empty disables", raw: "", total: 10, want: 0},
+		{name: "absolute count", raw: "5", total: 10, want: 5},
+		{name: "percentage rounds up", raw: "10%", total: 3, want: 1},
+		{name: "percentage exact", raw: "50%", total: 10, want: 5},
+		{name: "negative absolute rejected", raw: "-1", total: 10, wantErr: true},
+		{name: "percentage over 100 rejected", raw: "101%", total: 10, wantErr: true},
+		{name: "garbage rejected", raw: "abc", total: 10, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMaxUnavailable(tt.raw, tt.total)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMaxUnavailable(%q, %d) error = %v, wantErr %v", tt.raw, tt.total, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("ParseMaxUnavailable(%q, %d) = %d, want %d", tt.raw, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMinNodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		total   int
+		want    int
+		wantErr bool
+	}{
+		{name: "absolute count", raw: "3", total: 10, want: 3},
+		{name: "percentage of fleet", raw: "80%", total: 10, want: 8},
+		{name: "percentage rounds up", raw: "1%", total: 10, want: 1},
+		{name: "empty is not a shortcut, it's an error", raw: "", total: 10, wantErr: true},
+		{name: "negative absolute rejected", raw: "-3", total: 10, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMinNodes(tt.raw, tt.total)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMinNodes(%q, %d) error = %v, wantErr %v", tt.raw, tt.total, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("ParseMinNodes(%q, %d) = %d, want %d", tt.raw, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReapTaint(t *testing.T) {
+	key, value, err := ParseReapTaint("grim-reaper/reap=true")
+	if err != nil {
+		t.Fatalf("ParseReapTaint: unexpected error: %v", err)
+	}
+	if key != "grim-reaper/reap" || value != "true" {
+		t.Fatalf("ParseReapTaint = (%q, %q), want (%q, %q)", key, value, "grim-reaper/reap", "true")
+	}
+
+	if _, _, err := ParseReapTaint("no-equals-sign"); err == nil {
+		t.Fatal("ParseReapTaint(\"no-equals-sign\"): expected error, got nil")
+	}
+	if _, _, err := ParseReapTaint("=value"); err == nil {
+		t.Fatal("ParseReapTaint(\"=value\"): expected error for empty key, got nil")
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// envPrefix namespaces every environment variable grim-reaper reads, so it
+// doesn't collide with unrelated variables in the pod's environment.
+const envPrefix = "GRIM_REAPER_"
+
+// applyEnvOverrides sets every registered flag not already explicitly
+// passed on the command line from its GRIM_REAPER_<FLAG_NAME> environment
+// variable, if one is set. explicitFlags is updated in place so flags set
+// this way are treated as explicit for the purposes of the later config
+// file merge, giving the documented precedence: flags > env > config file.
+func applyEnvOverrides(explicitFlags map[string]bool) error {
+	var errs ValidationError
+
+	flag.VisitAll(func(f *flag.Flag) {
+		if explicitFlags[f.Name] {
+			return
+		}
+
+		envVar := envPrefix + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		value, ok := os.LookupEnv(envVar)
+		if !ok {
+			return
+		}
+
+		if err := f.Value.Set(value); err != nil {
+			errs = append(errs, FieldError{envVar, fmt.Sprintf("invalid value %q: %v", value, err)})
+			return
+		}
+		explicitFlags[f.Name] = true
+	})
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
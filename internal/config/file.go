@@ -0,0 +1,475 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileConfig mirrors Config's flag-settable fields for loading from
+// `--config=path.yaml`. It is a separate, flat type (rather than a
+// pointer-ified Config) so the YAML schema stays simple and readable.
+type FileConfig struct {
+	Kubeconfig     string `yaml:"kubeconfig"`
+	NodeSelector   string `yaml:"nodeSelector"`
+	Strategy       string `yaml:"strategy"`
+	ReapFraction   float64 `yaml:"reapFraction"`
+	MinNodes             string `yaml:"minNodes"`
+	MinNodesRequireReady bool `yaml:"minNodesRequireReady"`
+	MaxNodesDelete       int  `yaml:"maxNodesDelete"`
+	MaxUnavailable       string `yaml:"maxUnavailable"`
+	DryRun         bool    `yaml:"dryRun"`
+	Canary                   bool          `yaml:"canary"`
+	CanaryVerificationPeriod time.Duration `yaml:"canaryVerificationPeriod"`
+
+	LeaderElection bool   `yaml:"leaderElection"`
+	LeaseNamespace string `yaml:"leaseNamespace"`
+	LeaseName      string `yaml:"leaseName"`
+	LockType       string `yaml:"lockType"`
+	StatusAddr     string        `yaml:"statusAddr"`
+	StaleMarkerTTL time.Duration `yaml:"staleMarkerTTL"`
+	ReapTaint      string        `yaml:"reapTaint"`
+
+	RunInterval time.Duration `yaml:"runInterval"`
+
+	PlatformWebhookURL string            `yaml:"platformWebhookURL"`
+	NamespaceWebhooks  map[string]string `yaml:"namespaceWebhooks"`
+
+	CloudProvider           string        `yaml:"cloudProvider"`
+	CloudTerminationTimeout time.Duration `yaml:"cloudTerminationTimeout"`
+	InstanceDeletionMode    string        `yaml:"instanceDeletionMode"`
+	AsyncTermination        bool          `yaml:"asyncTermination"`
+	TerminationMaxRetries   int           `yaml:"terminationMaxRetries"`
+
+	PreAnnounceDuration time.Duration `yaml:"preAnnounceDuration"`
+
+	EvictionBackoffPolicy    string        `yaml:"evictionBackoffPolicy"`
+	EvictionBackoffBase      time.Duration `yaml:"evictionBackoffBase"`
+	EvictionBackoffMax       time.Duration `yaml:"evictionBackoffMax"`
+	EvictionMaxRetries       int           `yaml:"evictionMaxRetries"`
+	ForceDeleteAfterTimeout  bool          `yaml:"forceDeleteAfterTimeout"`
+	ForceDeleteGracePeriod   time.Duration `yaml:"forceDeleteGracePeriod"`
+	ExtendedGracePeriod      bool          `yaml:"extendedGracePeriod"`
+	MaxGracePeriodSeconds    int64         `yaml:"maxGracePeriodSeconds"`
+	EvictDeletionTimeout     time.Duration `yaml:"evictDeletionTimeout"`
+	MaxHarvestFailures       int           `yaml:"maxHarvestFailures"`
+	NodeGroupLabel           string        `yaml:"nodeGroupLabel"`
+	HonorSafeToEvict         bool          `yaml:"honorSafeToEvict"`
+	MaxPodDensity            int           `yaml:"maxPodDensity"`
+	YoungPodGrace            time.Duration `yaml:"youngPodGrace"`
+	PDBFeasibilityCheck      bool          `yaml:"pdbFeasibilityCheck"`
+	CapacityHeadroomCheck    bool          `yaml:"capacityHeadroomCheck"`
+	CapacityHeadroomMargin   float64       `yaml:"capacityHeadroomMargin"`
+	SchedulerSimulationCheck bool          `yaml:"schedulerSimulationCheck"`
+	PostHarvestVerification  bool          `yaml:"postHarvestVerification"`
+	VerifyPendingPodGrace    time.Duration `yaml:"verifyPendingPodGrace"`
+	PrometheusAlertGateURL   string        `yaml:"prometheusAlertGateUrl"`
+	PrometheusAlertGateQuery string        `yaml:"prometheusAlertGateQuery"`
+	ZoneBalanceMaxSkew       int           `yaml:"zoneBalanceMaxSkew"`
+
+	GCEProject   string `yaml:"gceProject"`
+	GCEGroupName string `yaml:"gceInstanceGroup"`
+	GCERegion    string `yaml:"gceRegion"`
+	GCEZone      string `yaml:"gceZone"`
+
+	PassoverLedgerEnabled       bool          `yaml:"passoverLedgerEnabled"`
+	PassoverLedgerTTL           time.Duration `yaml:"passoverLedgerTTL"`
+	PassoverEscalationThreshold int           `yaml:"passoverEscalationThreshold"`
+
+	ExternalDisruptionLedgerEnabled bool          `yaml:"externalDisruptionLedgerEnabled"`
+	ExternalDisruptionLedgerName    string        `yaml:"externalDisruptionLedgerName"`
+	ExternalDisruptionWindow        time.Duration `yaml:"externalDisruptionWindow"`
+
+	AzureResourceGroup  string `yaml:"azureResourceGroup"`
+	AzureScaleSetName   string `yaml:"azureScaleSet"`
+	AzureSubscriptionID string `yaml:"azureSubscriptionID"`
+
+	PodInformerCache               bool          `yaml:"podInformerCache"`
+	PodInformerSyncTimeout         time.Duration `yaml:"podInformerSyncTimeout"`
+	HarvestPacingBase              time.Duration `yaml:"harvestPacingBase"`
+	MaxConcurrentNodeDrains        int           `yaml:"maxConcurrentNodeDrains"`
+	BatchSize                      int           `yaml:"batchSize"`
+	BatchPause                     time.Duration `yaml:"batchPause"`
+	NodeCooldown                   time.Duration `yaml:"nodeCooldown"`
+	NodeCooldownWaitForPodsRunning bool          `yaml:"nodeCooldownWaitForPodsRunning"`
+	ReplacementReadyTimeout           time.Duration `yaml:"replacementReadyTimeout"`
+	MaxConsecutiveReplacementFailures int           `yaml:"maxConsecutiveReplacementFailures"`
+	MaxPendingPods                 int           `yaml:"maxPendingPods"`
+	MaxPendingPodAge               time.Duration `yaml:"maxPendingPodAge"`
+	BackpressureCheckInterval      time.Duration `yaml:"backpressureCheckInterval"`
+	BackpressureTimeout            time.Duration `yaml:"backpressureTimeout"`
+	MaxUnschedulablePods           int           `yaml:"maxUnschedulablePods"`
+	UnschedulablePodsAbort         bool          `yaml:"unschedulablePodsAbort"`
+
+	ObserverMode bool `yaml:"observerMode"`
+
+	DrainBackend  string `yaml:"drainBackend"`
+	BarePodPolicy string `yaml:"barePodPolicy"`
+
+	PushgatewayURL     string `yaml:"pushgatewayURL"`
+	PushgatewayJobName string `yaml:"pushgatewayJobName"`
+
+	Mode              string        `yaml:"mode"`
+	OutputFormat      string        `yaml:"outputFormat"`
+	RunIntervalJitter time.Duration `yaml:"runIntervalJitter"`
+
+	GenerateDashboardsDir string `yaml:"generateDashboardsDir"`
+	PrintConfig           bool   `yaml:"printConfig"`
+	ConfigzAddr           string `yaml:"configzAddr"`
+	ReportFile            string `yaml:"reportFile"`
+
+	Schedule                  string        `yaml:"schedule"`
+	MaintenanceWindowDuration time.Duration `yaml:"maintenanceWindowDuration"`
+
+	SoakDuration time.Duration `yaml:"soakDuration"`
+	SelfNodeName string        `yaml:"selfNodeName"`
+	PodName      string        `yaml:"podName"`
+	PodNamespace string        `yaml:"podNamespace"`
+	MaxNodeAge   time.Duration `yaml:"maxNodeAge"`
+	FakeCluster  string        `yaml:"fakeCluster"`
+
+	AttachHarvestTranscripts bool `yaml:"attachHarvestTranscripts"`
+
+	ScoreWeightAge        float64 `yaml:"scoreWeightAge"`
+	ScoreWeightPodCount   float64 `yaml:"scoreWeightPodCount"`
+	ScoreWeightRestarts   float64 `yaml:"scoreWeightRestarts"`
+	ScoreWeightCordoned   float64 `yaml:"scoreWeightCordoned"`
+	ScoreWeightAnnotation float64 `yaml:"scoreWeightAnnotation"`
+
+	TargetKubeletVersion string `yaml:"targetKubeletVersion"`
+	TargetImageRegex     string `yaml:"targetImageRegex"`
+
+	NotReadyGrace          time.Duration `yaml:"notReadyGrace"`
+	NotReadyMaxNodesDelete int           `yaml:"notReadyMaxNodesDelete"`
+
+	FeatureGates map[string]bool `yaml:"featureGates"`
+
+	AllowedTenantTiers []string `yaml:"allowedTenantTiers"`
+
+	RollbackOnShutdown bool `yaml:"rollbackOnShutdown"`
+
+	WarmupScanEnabled bool    `yaml:"warmupScanEnabled"`
+	WarmupScanQPS     float64 `yaml:"warmupScanQPS"`
+
+	StatefulReplicationRateMBPerSec float64 `yaml:"statefulReplicationRateMBPerSec"`
+	MaxStatefulGBPerRun             float64 `yaml:"maxStatefulGBPerRun"`
+
+	MaxConcurrentEvictions int     `yaml:"maxConcurrentEvictions"`
+	EvictionRateLimit      float64 `yaml:"evictionRateLimit"`
+	MaxMemoryMB            int     `yaml:"maxMemoryMB"`
+
+	CollectEventCorrelation bool `yaml:"collectEventCorrelation"`
+}
+
+// FieldError describes a single invalid or unparsable config field, named by
+// its dotted path in the source (flag name or YAML key).
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError aggregates every FieldError found while loading or
+// validating a Config, so a user fixing a config file sees every problem at
+// once instead of one per run.
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Error()
+	}
+	return fmt.Sprintf("invalid config:\n  %s", strings.Join(messages, "\n  "))
+}
+
+// loadConfigFile reads and parses path as a FileConfig. It rejects unknown
+// keys so a typo'd field name fails loudly instead of silently doing
+// nothing.
+func loadConfigFile(path string) (*FileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+
+	fc := &FileConfig{}
+	if err := decoder.Decode(fc); err != nil {
+		return nil, fmt.Errorf("parsing config file: %w", err)
+	}
+	return fc, nil
+}
+
+// mergeConfigFile applies fc onto c for every field whose flag was not
+// explicitly set on the command line, so flags always take precedence over
+// the config file.
+func mergeConfigFile(c *Config, fc *FileConfig, explicitFlags map[string]bool) {
+	set := func(flagName string, apply func()) {
+		if !explicitFlags[flagName] {
+			apply()
+		}
+	}
+
+	set("kubeconfig", func() { c.Kubeconfig = fc.Kubeconfig })
+	set("node-selector", func() { c.NodeSelector = fc.NodeSelector })
+	set("strategy", func() { c.Strategy = fc.Strategy })
+	set("reap-fraction", func() { c.ReapFraction = fc.ReapFraction })
+	set("min-nodes", func() { c.MinNodes = fc.MinNodes })
+	set("min-nodes-require-ready", func() { c.MinNodesRequireReady = fc.MinNodesRequireReady })
+	set("max-nodes-delete", func() { c.MaxNodesDelete = fc.MaxNodesDelete })
+	set("max-unavailable", func() { c.MaxUnavailable = fc.MaxUnavailable })
+	set("dry-run", func() { c.DryRun = fc.DryRun })
+	set("canary", func() { c.Canary = fc.Canary })
+	set("canary-verification-period", func() { c.CanaryVerificationPeriod = fc.CanaryVerificationPeriod })
+	set("leader-election", func() { c.LeaderElection = fc.LeaderElection })
+	set("leader-election-namespace", func() { c.LeaseNamespace = fc.LeaseNamespace })
+	set("leader-election-lock-name", func() { c.LeaseName = fc.LeaseName })
+	set("lock-type", func() { c.LockType = fc.LockType })
+	set("status-addr", func() { c.StatusAddr = fc.StatusAddr })
+	set("stale-marker-ttl", func() { c.StaleMarkerTTL = fc.StaleMarkerTTL })
+	set("reap-taint", func() { c.ReapTaint = fc.ReapTaint })
+	set("run-interval", func() { c.RunInterval = fc.RunInterval })
+	set("platform-webhook-url", func() { c.PlatformWebhookURL = fc.PlatformWebhookURL })
+	if !explicitFlags["namespace-webhooks"] && len(fc.NamespaceWebhooks) > 0 {
+		c.NamespaceWebhooks = fc.NamespaceWebhooks
+	}
+	set("cloud-provider", func() { c.CloudProvider = fc.CloudProvider })
+	set("cloud-termination-timeout", func() { c.CloudTerminationTimeout = fc.CloudTerminationTimeout })
+	set("instance-deletion-mode", func() { c.InstanceDeletionMode = fc.InstanceDeletionMode })
+	set("async-termination", func() { c.AsyncTermination = fc.AsyncTermination })
+	set("termination-max-retries", func() { c.TerminationMaxRetries = fc.TerminationMaxRetries })
+	set("pre-announce-duration", func() { c.PreAnnounceDuration = fc.PreAnnounceDuration })
+	set("eviction-backoff-policy", func() { c.EvictionBackoffPolicy = fc.EvictionBackoffPolicy })
+	set("eviction-backoff-base", func() { c.EvictionBackoffBase = fc.EvictionBackoffBase })
+	set("eviction-backoff-max", func() { c.EvictionBackoffMax = fc.EvictionBackoffMax })
+	set("eviction-max-retries", func() { c.EvictionMaxRetries = fc.EvictionMaxRetries })
+	set("force-delete-after-timeout", func() { c.ForceDeleteAfterTimeout = fc.ForceDeleteAfterTimeout })
+	set("force-delete-grace-period", func() { c.ForceDeleteGracePeriod = fc.ForceDeleteGracePeriod })
+	set("extended-grace-period", func() { c.ExtendedGracePeriod = fc.ExtendedGracePeriod })
+	set("max-grace-period-seconds", func() { c.MaxGracePeriodSeconds = fc.MaxGracePeriodSeconds })
+	set("evict-deletion-timeout", func() { c.EvictDeletionTimeout = fc.EvictDeletionTimeout })
+	set("max-harvest-failures", func() { c.MaxHarvestFailures = fc.MaxHarvestFailures })
+	set("node-group-label", func() { c.NodeGroupLabel = fc.NodeGroupLabel })
+	set("honor-safe-to-evict", func() { c.HonorSafeToEvict = fc.HonorSafeToEvict })
+	set("max-pod-density", func() { c.MaxPodDensity = fc.MaxPodDensity })
+	set("young-pod-grace", func() { c.YoungPodGrace = fc.YoungPodGrace })
+	set("pdb-feasibility-check", func() { c.PDBFeasibilityCheck = fc.PDBFeasibilityCheck })
+	set("capacity-headroom-check", func() { c.CapacityHeadroomCheck = fc.CapacityHeadroomCheck })
+	set("capacity-headroom-margin", func() { c.CapacityHeadroomMargin = fc.CapacityHeadroomMargin })
+	set("scheduler-simulation-check", func() { c.SchedulerSimulationCheck = fc.SchedulerSimulationCheck })
+	set("post-harvest-verification", func() { c.PostHarvestVerification = fc.PostHarvestVerification })
+	set("verify-pending-pod-grace", func() { c.VerifyPendingPodGrace = fc.VerifyPendingPodGrace })
+	set("prometheus-alert-gate-url", func() { c.PrometheusAlertGateURL = fc.PrometheusAlertGateURL })
+	set("prometheus-alert-gate-query", func() { c.PrometheusAlertGateQuery = fc.PrometheusAlertGateQuery })
+	set("zone-balance-max-skew", func() { c.ZoneBalanceMaxSkew = fc.ZoneBalanceMaxSkew })
+	set("gce-project", func() { c.GCEProject = fc.GCEProject })
+	set("gce-instance-group", func() { c.GCEGroupName = fc.GCEGroupName })
+	set("gce-region", func() { c.GCERegion = fc.GCERegion })
+	set("gce-zone", func() { c.GCEZone = fc.GCEZone })
+	set("passover-ledger-enabled", func() { c.PassoverLedgerEnabled = fc.PassoverLedgerEnabled })
+	set("passover-ledger-ttl", func() { c.PassoverLedgerTTL = fc.PassoverLedgerTTL })
+	set("passover-escalation-threshold", func() { c.PassoverEscalationThreshold = fc.PassoverEscalationThreshold })
+	set("external-disruption-ledger-enabled", func() { c.ExternalDisruptionLedgerEnabled = fc.ExternalDisruptionLedgerEnabled })
+	set("external-disruption-ledger-name", func() { c.ExternalDisruptionLedgerName = fc.ExternalDisruptionLedgerName })
+	set("external-disruption-window", func() { c.ExternalDisruptionWindow = fc.ExternalDisruptionWindow })
+	set("azure-resource-group", func() { c.AzureResourceGroup = fc.AzureResourceGroup })
+	set("azure-scale-set", func() { c.AzureScaleSetName = fc.AzureScaleSetName })
+	set("azure-subscription-id", func() { c.AzureSubscriptionID = fc.AzureSubscriptionID })
+	set("pod-informer-cache", func() { c.PodInformerCache = fc.PodInformerCache })
+	set("pod-informer-sync-timeout", func() { c.PodInformerSyncTimeout = fc.PodInformerSyncTimeout })
+	set("harvest-pacing-base", func() { c.HarvestPacingBase = fc.HarvestPacingBase })
+	set("max-concurrent-node-drains", func() { c.MaxConcurrentNodeDrains = fc.MaxConcurrentNodeDrains })
+	set("batch-size", func() { c.BatchSize = fc.BatchSize })
+	set("batch-pause", func() { c.BatchPause = fc.BatchPause })
+	set("node-cooldown", func() { c.NodeCooldown = fc.NodeCooldown })
+	set("node-cooldown-wait-for-pods-running", func() { c.NodeCooldownWaitForPodsRunning = fc.NodeCooldownWaitForPodsRunning })
+	set("replacement-ready-timeout", func() { c.ReplacementReadyTimeout = fc.ReplacementReadyTimeout })
+	set("max-consecutive-replacement-failures", func() { c.MaxConsecutiveReplacementFailures = fc.MaxConsecutiveReplacementFailures })
+	set("max-pending-pods", func() { c.MaxPendingPods = fc.MaxPendingPods })
+	set("max-pending-pod-age", func() { c.MaxPendingPodAge = fc.MaxPendingPodAge })
+	set("backpressure-check-interval", func() { c.BackpressureCheckInterval = fc.BackpressureCheckInterval })
+	set("backpressure-timeout", func() { c.BackpressureTimeout = fc.BackpressureTimeout })
+	set("max-unschedulable-pods", func() { c.MaxUnschedulablePods = fc.MaxUnschedulablePods })
+	set("unschedulable-pods-abort", func() { c.UnschedulablePodsAbort = fc.UnschedulablePodsAbort })
+	set("observer-mode", func() { c.ObserverMode = fc.ObserverMode })
+	set("drain-backend", func() { c.DrainBackend = fc.DrainBackend })
+	set("bare-pod-policy", func() { c.BarePodPolicy = fc.BarePodPolicy })
+	set("pushgateway-url", func() { c.PushgatewayURL = fc.PushgatewayURL })
+	set("pushgateway-job-name", func() { c.PushgatewayJobName = fc.PushgatewayJobName })
+	set("mode", func() { c.Mode = fc.Mode })
+	set("output", func() { c.OutputFormat = fc.OutputFormat })
+	set("run-interval-jitter", func() { c.RunIntervalJitter = fc.RunIntervalJitter })
+	set("generate-dashboards", func() { c.GenerateDashboardsDir = fc.GenerateDashboardsDir })
+	set("print-config", func() { c.PrintConfig = fc.PrintConfig })
+	set("configz-addr", func() { c.ConfigzAddr = fc.ConfigzAddr })
+	set("report-file", func() { c.ReportFile = fc.ReportFile })
+	set("schedule", func() { c.Schedule = fc.Schedule })
+	set("maintenance-window-duration", func() { c.MaintenanceWindowDuration = fc.MaintenanceWindowDuration })
+	set("soak-duration", func() { c.SoakDuration = fc.SoakDuration })
+	set("self-node-name", func() { c.SelfNodeName = fc.SelfNodeName })
+	set("pod-name", func() { c.PodName = fc.PodName })
+	set("pod-namespace", func() { c.PodNamespace = fc.PodNamespace })
+	set("max-node-age", func() { c.MaxNodeAge = fc.MaxNodeAge })
+	set("fake-cluster", func() { c.FakeCluster = fc.FakeCluster })
+	set("attach-harvest-transcripts", func() { c.AttachHarvestTranscripts = fc.AttachHarvestTranscripts })
+	set("score-weight-age", func() { c.ScoreWeightAge = fc.ScoreWeightAge })
+	set("score-weight-pod-count", func() { c.ScoreWeightPodCount = fc.ScoreWeightPodCount })
+	set("score-weight-restarts", func() { c.ScoreWeightRestarts = fc.ScoreWeightRestarts })
+	set("score-weight-cordoned", func() { c.ScoreWeightCordoned = fc.ScoreWeightCordoned })
+	set("score-weight-annotation", func() { c.ScoreWeightAnnotation = fc.ScoreWeightAnnotation })
+	set("target-kubelet-version", func() { c.TargetKubeletVersion = fc.TargetKubeletVersion })
+	set("target-image-regex", func() { c.TargetImageRegex = fc.TargetImageRegex })
+	set("not-ready-grace", func() { c.NotReadyGrace = fc.NotReadyGrace })
+	set("not-ready-max-nodes-delete", func() { c.NotReadyMaxNodesDelete = fc.NotReadyMaxNodesDelete })
+	if !explicitFlags["feature-gates"] && len(fc.FeatureGates) > 0 {
+		c.FeatureGates = fc.FeatureGates
+	}
+	if !explicitFlags["allowed-tenant-tiers"] && len(fc.AllowedTenantTiers) > 0 {
+		c.AllowedTenantTiers = fc.AllowedTenantTiers
+	}
+	set("rollback-on-shutdown", func() { c.RollbackOnShutdown = fc.RollbackOnShutdown })
+	set("warmup-scan", func() { c.WarmupScanEnabled = fc.WarmupScanEnabled })
+	set("warmup-scan-qps", func() { c.WarmupScanQPS = fc.WarmupScanQPS })
+	set("stateful-replication-rate-mb-per-sec", func() { c.StatefulReplicationRateMBPerSec = fc.StatefulReplicationRateMBPerSec })
+	set("max-stateful-gb-per-run", func() { c.MaxStatefulGBPerRun = fc.MaxStatefulGBPerRun })
+	set("max-concurrent-evictions", func() { c.MaxConcurrentEvictions = fc.MaxConcurrentEvictions })
+	set("eviction-rate-limit", func() { c.EvictionRateLimit = fc.EvictionRateLimit })
+	set("max-memory-mb", func() { c.MaxMemoryMB = fc.MaxMemoryMB })
+	set("collect-event-correlation", func() { c.CollectEventCorrelation = fc.CollectEventCorrelation })
+}
+
+// Validate checks c for values that parsed fine as their Go type but are
+// not in the set of values grim-reaper actually understands, returning
+// every problem found rather than just the first.
+func Validate(c *Config) error {
+	var errs ValidationError
+
+	switch c.Strategy {
+	case "", "oldest", "newest", "random", "emptiest", "fragmentation", "most-restarts", "weighted":
+	default:
+		errs = append(errs, FieldError{"strategy", fmt.Sprintf("unsupported strategy %q", c.Strategy)})
+	}
+
+	if c.ReapFraction < 0 || c.ReapFraction > 1 {
+		errs = append(errs, FieldError{"reapFraction", "must be between 0 and 1"})
+	}
+
+	if _, err := ParseMinNodes(c.MinNodes, 0); err != nil {
+		errs = append(errs, FieldError{"minNodes", err.Error()})
+	}
+
+	if c.MaxNodesDelete < 0 {
+		errs = append(errs, FieldError{"maxNodesDelete", "must not be negative"})
+	}
+
+	if c.ExtendedGracePeriod && c.MaxGracePeriodSeconds <= 0 {
+		errs = append(errs, FieldError{"maxGracePeriodSeconds", "must be positive when extendedGracePeriod is set"})
+	}
+
+	switch c.CloudProvider {
+	case "", "none", "aws", "gce", "azure":
+	default:
+		errs = append(errs, FieldError{"cloudProvider", fmt.Sprintf("unsupported cloud provider %q", c.CloudProvider)})
+	}
+
+	switch c.EvictionBackoffPolicy {
+	case "constant", "exponential", "decorrelated-jitter", "jittered-exponential":
+	default:
+		errs = append(errs, FieldError{"evictionBackoffPolicy", fmt.Sprintf("unsupported backoff policy %q", c.EvictionBackoffPolicy)})
+	}
+
+	switch c.DrainBackend {
+	case "custom", "kubectl":
+	default:
+		errs = append(errs, FieldError{"drainBackend", fmt.Sprintf("unsupported drain backend %q", c.DrainBackend)})
+	}
+
+	switch c.LockType {
+	case "leases":
+	default:
+		errs = append(errs, FieldError{"lockType", fmt.Sprintf("unsupported lock type %q", c.LockType)})
+	}
+
+	switch c.InstanceDeletionMode {
+	case "", "shrink", "recycle", "detach-terminate":
+	default:
+		errs = append(errs, FieldError{"instanceDeletionMode", fmt.Sprintf("unsupported instance deletion mode %q", c.InstanceDeletionMode)})
+	}
+
+	switch c.BarePodPolicy {
+	case "skip", "evict", "passover-node", "require-approval":
+	default:
+		errs = append(errs, FieldError{"barePodPolicy", fmt.Sprintf("unsupported bare-pod policy %q", c.BarePodPolicy)})
+	}
+
+	if c.TargetImageRegex != "" {
+		if _, err := regexp.Compile(c.TargetImageRegex); err != nil {
+			errs = append(errs, FieldError{"targetImageRegex", fmt.Sprintf("invalid regex: %v", err)})
+		}
+	}
+
+	switch c.Mode {
+	case "run", "daemon":
+	default:
+		errs = append(errs, FieldError{"mode", fmt.Sprintf("unsupported mode %q", c.Mode)})
+	}
+
+	switch c.OutputFormat {
+	case "", "table", "json", "yaml":
+	default:
+		errs = append(errs, FieldError{"outputFormat", fmt.Sprintf("unsupported output format %q", c.OutputFormat)})
+	}
+
+	if c.ReapTaint != "" {
+		if _, _, err := ParseReapTaint(c.ReapTaint); err != nil {
+			errs = append(errs, FieldError{"reapTaint", err.Error()})
+		}
+	}
+
+	if c.MaxUnavailable != "" {
+		if _, err := ParseMaxUnavailable(c.MaxUnavailable, 0); err != nil {
+			errs = append(errs, FieldError{"maxUnavailable", err.Error()})
+		}
+	}
+
+	if c.MaxConcurrentEvictions < 0 {
+		errs = append(errs, FieldError{"maxConcurrentEvictions", "must not be negative"})
+	}
+
+	if c.MaxConcurrentNodeDrains < 0 {
+		errs = append(errs, FieldError{"maxConcurrentNodeDrains", "must not be negative"})
+	}
+
+	if c.BatchSize < 0 {
+		errs = append(errs, FieldError{"batchSize", "must not be negative"})
+	}
+
+	if c.EvictionRateLimit < 0 {
+		errs = append(errs, FieldError{"evictionRateLimit", "must not be negative"})
+	}
+
+	if c.MaxConsecutiveReplacementFailures < 0 {
+		errs = append(errs, FieldError{"maxConsecutiveReplacementFailures", "must not be negative"})
+	}
+
+	if c.CapacityHeadroomMargin < 0 {
+		errs = append(errs, FieldError{"capacityHeadroomMargin", "must not be negative"})
+	}
+
+	if c.MaxMemoryMB < 0 {
+		errs = append(errs, FieldError{"maxMemoryMB", "must not be negative"})
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
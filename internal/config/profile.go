@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// profileDefaults is the subset of Config fields a --profile preset
+// overrides. Only budgets, timeouts, and safety gates are bundled here;
+// everything else keeps its ordinary hardcoded default regardless of
+// profile.
+type profileDefaults struct {
+	ReapFraction                float64
+	MaxNodesDelete              int
+	MaxConcurrentEvictions      int
+	EvictionMaxRetries          int
+	EvictDeletionTimeout        time.Duration
+	HonorSafeToEvict            bool
+	SoakDuration                time.Duration
+	RollbackOnShutdown          bool
+	MaxHarvestFailures          int
+	PassoverEscalationThreshold int
+	MaxPendingPods              int
+	BackpressureTimeout         time.Duration
+}
+
+// profiles holds the built-in --profile presets. "conservative" favors
+// never surprising an operator over throughput: small batches, generous
+// retries, and a rollback safety net. "aggressive" favors clearing nodes
+// quickly and accepts more risk of a rejected eviction or a disruptive
+// shutdown. "balanced" sits at grim-reaper's own long-standing hardcoded
+// defaults, so choosing it is a no-op next to leaving --profile unset.
+var profiles = map[string]profileDefaults{
+	"conservative": {
+		ReapFraction:                0.05,
+		MaxNodesDelete:              1,
+		MaxConcurrentEvictions:      1,
+		EvictionMaxRetries:          20,
+		EvictDeletionTimeout:        5 * time.Minute,
+		HonorSafeToEvict:            true,
+		SoakDuration:                30 * time.Minute,
+		RollbackOnShutdown:          true,
+		MaxHarvestFailures:          1,
+		PassoverEscalationThreshold: 3,
+		MaxPendingPods:              0,
+		BackpressureTimeout:         0,
+	},
+	"balanced": {
+		ReapFraction:                0.1,
+		MaxNodesDelete:              5,
+		MaxConcurrentEvictions:      1,
+		EvictionMaxRetries:          12,
+		EvictDeletionTimeout:        2 * time.Minute,
+		HonorSafeToEvict:            true,
+		SoakDuration:                0,
+		RollbackOnShutdown:          false,
+		MaxHarvestFailures:          3,
+		PassoverEscalationThreshold: 5,
+		MaxPendingPods:              0,
+		BackpressureTimeout:         0,
+	},
+	"aggressive": {
+		ReapFraction:                0.25,
+		MaxNodesDelete:              20,
+		MaxConcurrentEvictions:      4,
+		EvictionMaxRetries:          6,
+		EvictDeletionTimeout:        30 * time.Second,
+		HonorSafeToEvict:            false,
+		SoakDuration:                0,
+		RollbackOnShutdown:          false,
+		MaxHarvestFailures:          0,
+		PassoverEscalationThreshold: 10,
+		MaxPendingPods:              0,
+		BackpressureTimeout:         5 * time.Minute,
+	},
+}
+
+// applyProfile sets c's budgets, timeouts, and gates from the named preset
+// in c.Profile, skipping any field whose flag was already explicitly
+// passed on the command line. Unlike applyEnvOverrides, it does not mark
+// the fields it sets as explicit: a profile is the coarsest, lowest-
+// precedence layer, so an env var or --config entry tuning one of the same
+// settings still takes effect over it, giving flags > env > config file >
+// profile. An empty c.Profile is a no-op; any other unrecognized value is
+// a validation error.
+func applyProfile(c *Config, explicitFlags map[string]bool) error {
+	if c.Profile == "" {
+		return nil
+	}
+
+	defaults, ok := profiles[c.Profile]
+	if !ok {
+		return ValidationError{{"profile", fmt.Sprintf("unknown profile %q", c.Profile)}}
+	}
+
+	set := func(flagName string, apply func()) {
+		if !explicitFlags[flagName] {
+			apply()
+		}
+	}
+
+	set("reap-fraction", func() { c.ReapFraction = defaults.ReapFraction })
+	set("max-nodes-delete", func() { c.MaxNodesDelete = defaults.MaxNodesDelete })
+	set("max-concurrent-evictions", func() { c.MaxConcurrentEvictions = defaults.MaxConcurrentEvictions })
+	set("eviction-max-retries", func() { c.EvictionMaxRetries = defaults.EvictionMaxRetries })
+	set("evict-deletion-timeout", func() { c.EvictDeletionTimeout = defaults.EvictDeletionTimeout })
+	set("honor-safe-to-evict", func() { c.HonorSafeToEvict = defaults.HonorSafeToEvict })
+	set("soak-duration", func() { c.SoakDuration = defaults.SoakDuration })
+	set("rollback-on-shutdown", func() { c.RollbackOnShutdown = defaults.RollbackOnShutdown })
+	set("max-harvest-failures", func() { c.MaxHarvestFailures = defaults.MaxHarvestFailures })
+	set("passover-escalation-threshold", func() { c.PassoverEscalationThreshold = defaults.PassoverEscalationThreshold })
+	set("max-pending-pods", func() { c.MaxPendingPods = defaults.MaxPendingPods })
+	set("backpressure-timeout", func() { c.BackpressureTimeout = defaults.BackpressureTimeout })
+
+	return nil
+}
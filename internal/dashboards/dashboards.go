@@ -0,0 +1,71 @@
+// Package dashboards generates a Grafana dashboard and matching
+// PrometheusRule alerts from grim-reaper's own metric definitions, so the
+// two never drift out of sync with the code that emits them.
+package dashboards
+
+import (
+	"encoding/json"
+
+	"github.com/BrianKopp/grim-reaper/internal/metrics"
+)
+
+// Dashboard returns a Grafana dashboard JSON document with one panel per
+// metric in metrics.Defs.
+func Dashboard() ([]byte, error) {
+	panels := make([]map[string]interface{}, 0, len(metrics.Defs))
+	for i, m := range metrics.Defs {
+		panels = append(panels, map[string]interface{}{
+			"id":    i + 1,
+			"title": m.Name,
+			"type":  panelType(m.Type),
+			"targets": []map[string]interface{}{
+				{"expr": m.Name},
+			},
+		})
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"title":  "grim-reaper",
+		"panels": panels,
+	}, "", "  ")
+}
+
+func panelType(metricType string) string {
+	if metricType == "histogram" {
+		return "heatmap"
+	}
+	return "timeseries"
+}
+
+// AlertRules returns a PrometheusRule manifest (as YAML-shaped JSON, which
+// is valid YAML) alerting when the observer reports unexpectedly many
+// blocked pods.
+func AlertRules() ([]byte, error) {
+	return json.MarshalIndent(map[string]interface{}{
+		"apiVersion": "monitoring.coreos.com/v1",
+		"kind":       "PrometheusRule",
+		"metadata": map[string]string{
+			"name": "grim-reaper-alerts",
+		},
+		"spec": map[string]interface{}{
+			"groups": []map[string]interface{}{
+				{
+					"name": "grim-reaper",
+					"rules": []map[string]interface{}{
+						{
+							"alert": "GrimReaperManyBlockedEvictions",
+							"expr":  "sum(grim_reaper_observer_simulated_drain_blocked_pods) > 10",
+							"for":   "15m",
+							"labels": map[string]string{
+								"severity": "warning",
+							},
+							"annotations": map[string]string{
+								"summary": "grim-reaper is observing many pods it could not evict.",
+							},
+						},
+					},
+				},
+			},
+		},
+	}, "", "  ")
+}
@@ -0,0 +1,45 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/BrianKopp/grim-reaper/internal/metrics"
+)
+
+// TestDashboardHasOnePanelPerMetric is the anti-drift regression: every
+// metric registered in internal/metrics must get a panel, with no separate
+// hand-maintained list to fall out of sync.
+func TestDashboardHasOnePanelPerMetric(t *testing.T) {
+	raw, err := Dashboard()
+	if err != nil {
+		t.Fatalf("Dashboard: unexpected error: %v", err)
+	}
+
+	var doc struct {
+		Panels []map[string]interface{} `json:"panels"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("Dashboard: unparseable output: %v", err)
+	}
+
+	if len(doc.Panels) != len(metrics.Defs) {
+		t.Fatalf("Dashboard produced %d panels, want %d (one per metrics.Defs entry)", len(doc.Panels), len(metrics.Defs))
+	}
+
+	titles := make(map[string]bool, len(doc.Panels))
+	for _, p := range doc.Panels {
+		titles[p["title"].(string)] = true
+	}
+	for _, m := range metrics.Defs {
+		if !titles[m.Name] {
+			t.Fatalf("Dashboard has no panel titled %q", m.Name)
+		}
+	}
+}
+
+func TestAlertRulesProducesValidJSON(t *testing.T) {
+	if _, err := AlertRules(); err != nil {
+		t.Fatalf("AlertRules: unexpected error: %v", err)
+	}
+}
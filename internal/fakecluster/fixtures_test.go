@@ -0,0 +1,99 @@
+package fakecluster
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseSpec(t *testing.T) {
+	if spec, err := ParseSpec(""); err != nil || spec != DefaultSpec {
+		t.Fatalf("ParseSpec(\"\") = (%v, %v), want (%v, nil)", spec, err, DefaultSpec)
+	}
+
+	spec, err := ParseSpec("nodes=50,pods-per-node=30")
+	if err != nil {
+		t.Fatalf("ParseSpec: unexpected error: %v", err)
+	}
+	if spec.Nodes != 50 || spec.PodsPerNode != 30 {
+		t.Fatalf("ParseSpec(\"nodes=50,pods-per-node=30\") = %+v, want {Nodes:50 PodsPerNode:30}", spec)
+	}
+
+	if _, err := ParseSpec("nodes=fifty"); err == nil {
+		t.Fatal("ParseSpec(\"nodes=fifty\"): expected error for non-numeric value, got nil")
+	}
+	if _, err := ParseSpec("bogus-key=1"); err == nil {
+		t.Fatal("ParseSpec(\"bogus-key=1\"): expected error for unknown key, got nil")
+	}
+}
+
+func TestNewBuildsRequestedShape(t *testing.T) {
+	clientset := New(Spec{Nodes: 3, PodsPerNode: 2})
+
+	nodes, err := clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("listing fake nodes: %v", err)
+	}
+	if len(nodes.Items) != 3 {
+		t.Fatalf("New(Nodes:3) produced %d nodes, want 3", len(nodes.Items))
+	}
+
+	pods, err := clientset.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("listing fake pods: %v", err)
+	}
+	if len(pods.Items) != 6 {
+		t.Fatalf("New(Nodes:3, PodsPerNode:2) produced %d pods, want 6", len(pods.Items))
+	}
+}
+
+func TestLoad(t *testing.T) {
+	clientset, err := Load("nodes=2,pods-per-node=1")
+	if err != nil {
+		t.Fatalf("Load(synthetic spec): unexpected error: %v", err)
+	}
+	nodes, err := clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil || len(nodes.Items) != 2 {
+		t.Fatalf("Load(synthetic spec) produced %d nodes (err=%v), want 2", len(nodes.Items), err)
+	}
+
+	if _, err := Load("scenario=pdb-blocked"); err != nil {
+		t.Fatalf("Load(scenario=pdb-blocked): unexpected error: %v", err)
+	}
+
+	if _, err := Load("scenario=does-not-exist"); err == nil {
+		t.Fatal("Load(scenario=does-not-exist): expected error, got nil")
+	}
+}
+
+func TestBuildScenarios(t *testing.T) {
+	for _, name := range []Scenario{ScenarioPDBBlocked, ScenarioUnreachableNode, ScenarioDenseNode} {
+		clientset, err := BuildScenario(string(name))
+		if err != nil {
+			t.Fatalf("BuildScenario(%q): unexpected error: %v", name, err)
+		}
+		nodes, err := clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+		if err != nil || len(nodes.Items) != 1 {
+			t.Fatalf("BuildScenario(%q) produced %d nodes (err=%v), want 1", name, len(nodes.Items), err)
+		}
+	}
+
+	if _, err := BuildScenario("unknown-scenario"); err == nil {
+		t.Fatal("BuildScenario(unknown-scenario): expected error, got nil")
+	}
+}
+
+func TestDenseNodeScenarioHasManyPods(t *testing.T) {
+	clientset, err := BuildScenario(string(ScenarioDenseNode))
+	if err != nil {
+		t.Fatalf("BuildScenario(dense-node): unexpected error: %v", err)
+	}
+	pods, err := clientset.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("listing pods: %v", err)
+	}
+	if len(pods.Items) != 200 {
+		t.Fatalf("dense-node scenario has %d pods, want 200", len(pods.Items))
+	}
+}
@@ -0,0 +1,91 @@
+// Package leaderelection wraps client-go's lease-based leader election so
+// only one grim-reaper replica actively reaps at a time, while every
+// replica of an HA pair can still report whether it's currently leading.
+package leaderelection
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// Elector campaigns for a Lease and reports whether this replica currently
+// holds it.
+type Elector struct {
+	elector *leaderelection.LeaderElector
+	leading atomic.Bool
+}
+
+// New returns an Elector backed by a Lease named name in namespace,
+// identified as this process's hostname (the pod name, under the standard
+// Deployment manifest). lockType selects the resource backing the lock;
+// "leases" is the only type client-go v0.28 still supports (the
+// ConfigMap and dual ConfigMap+Lease backends were removed upstream).
+func New(clientset kubernetes.Interface, namespace, name, lockType string) (*Elector, error) {
+	identity, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("determining leader election identity: %w", err)
+	}
+
+	resourceLockType, err := resourceLockType(lockType)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := resourcelock.New(resourceLockType, namespace, name,
+		clientset.CoreV1(), clientset.CoordinationV1(),
+		resourcelock.ResourceLockConfig{Identity: identity})
+	if err != nil {
+		return nil, fmt.Errorf("building leader election lock: %w", err)
+	}
+
+	e := &Elector{}
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   15 * time.Second,
+		RenewDeadline:   10 * time.Second,
+		RetryPeriod:     2 * time.Second,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) { e.leading.Store(true) },
+			OnStoppedLeading: func() { e.leading.Store(false) },
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("building leader elector: %w", err)
+	}
+	e.elector = elector
+	return e, nil
+}
+
+// Run campaigns for the lease, renewing it for as long as this replica
+// holds it, until ctx is canceled. If this replica is leading when ctx is
+// canceled, the lease is released immediately (ReleaseOnCancel) so a
+// replacement reaper doesn't have to wait out the full LeaseDuration before
+// taking over. It blocks; callers should run it in its own goroutine.
+func (e *Elector) Run(ctx context.Context) {
+	e.elector.Run(ctx)
+}
+
+// IsLeader reports whether this replica currently holds the lease.
+func (e *Elector) IsLeader() bool {
+	return e.leading.Load()
+}
+
+// resourceLockType maps lockType to the resourcelock type it names.
+// Validated in config, so an unrecognized value here indicates a caller
+// bug rather than bad user input.
+func resourceLockType(lockType string) (string, error) {
+	switch lockType {
+	case "leases":
+		return resourcelock.LeasesResourceLock, nil
+	default:
+		return "", fmt.Errorf("unsupported lock type %q", lockType)
+	}
+}
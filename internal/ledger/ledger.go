@@ -0,0 +1,183 @@
+// Package ledger persists a record of nodes grim-reaper has passed over,
+// so repeated passovers for the same transient reason can be escalated
+// instead of retried forever.
+package ledger
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Entry is a single recorded passover.
+type Entry struct {
+	Node      string    `json:"node"`
+	Reason    string    `json:"reason"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Ledger records passovers and reports how many times a node has been
+// passed over for the same reason within a TTL window.
+type Ledger interface {
+	// Record appends a passover entry and returns how many times node has
+	// been passed over for reason within the configured TTL, including
+	// this one.
+	Record(node, reason string) (count int, err error)
+
+	// Prune drops entries older than the configured TTL.
+	Prune() error
+}
+
+// configMapLedger persists entries in a single ConfigMap, keyed by node
+// name, so the ledger survives restarts without a separate datastore.
+type configMapLedger struct {
+	clientset kubernetes.Interface
+	namespace string
+	name      string
+	ttl       time.Duration
+}
+
+// NewConfigMapLedger returns a Ledger backed by a ConfigMap named name in
+// namespace, retaining entries for ttl.
+func NewConfigMapLedger(clientset kubernetes.Interface, namespace, name string, ttl time.Duration) Ledger {
+	return &configMapLedger{clientset: clientset, namespace: namespace, name: name, ttl: ttl}
+}
+
+func (l *configMapLedger) load(ctx context.Context) (*corev1.ConfigMap, []Entry, error) {
+	cm, err := l.clientset.CoreV1().ConfigMaps(l.namespace).Get(ctx, l.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: l.name, Namespace: l.namespace},
+			Data:       map[string]string{},
+		}
+		return cm, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var entries []Entry
+	if raw, ok := cm.Data["entries"]; ok {
+		if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+			return nil, nil, err
+		}
+	}
+	return cm, entries, nil
+}
+
+func (l *configMapLedger) save(ctx context.Context, cm *corev1.ConfigMap, entries []Entry) error {
+	raw, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data["entries"] = string(raw)
+
+	if cm.ResourceVersion == "" {
+		_, err = l.clientset.CoreV1().ConfigMaps(l.namespace).Create(ctx, cm, metav1.CreateOptions{})
+	} else {
+		_, err = l.clientset.CoreV1().ConfigMaps(l.namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+func (l *configMapLedger) Record(node, reason string) (int, error) {
+	ctx := context.Background()
+	cm, entries, err := l.load(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	entries = dropExpired(entries, l.ttl)
+	entries = append(entries, Entry{Node: node, Reason: reason, Timestamp: time.Now()})
+
+	if err := l.save(ctx, cm, entries); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, e := range entries {
+		if e.Node == node && e.Reason == reason {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (l *configMapLedger) Prune() error {
+	ctx := context.Background()
+	cm, entries, err := l.load(ctx)
+	if err != nil {
+		return err
+	}
+	return l.save(ctx, cm, dropExpired(entries, l.ttl))
+}
+
+// DisruptionReader reports how many node disruptions other tools --
+// cluster upgrade controllers, chaos engineering tools, anything that
+// cordons or deletes nodes outside of grim-reaper -- have recorded
+// recently, so grim-reaper can count them against its own reap budget
+// instead of treating its budget as the only source of churn.
+type DisruptionReader interface {
+	// CountRecent returns the number of disruptions recorded within the
+	// last window.
+	CountRecent(window time.Duration) (int, error)
+}
+
+// configMapDisruptionReader reads disruption entries another tool has
+// written into a ConfigMap, using the same {node, reason, timestamp}
+// entry shape a configMapLedger writes, so a single convention covers
+// both grim-reaper's own passover ledger and an externally-maintained
+// disruption ledger.
+type configMapDisruptionReader struct {
+	clientset kubernetes.Interface
+	namespace string
+	name      string
+}
+
+// NewConfigMapDisruptionReader returns a DisruptionReader that reads
+// entries from the ConfigMap named name in namespace. It never writes to
+// the ConfigMap; the external tool populating it owns that.
+func NewConfigMapDisruptionReader(clientset kubernetes.Interface, namespace, name string) DisruptionReader {
+	return &configMapDisruptionReader{clientset: clientset, namespace: namespace, name: name}
+}
+
+func (r *configMapDisruptionReader) CountRecent(window time.Duration) (int, error) {
+	ctx := context.Background()
+	cm, err := r.clientset.CoreV1().ConfigMaps(r.namespace).Get(ctx, r.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var entries []Entry
+	if raw, ok := cm.Data["entries"]; ok {
+		if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+			return 0, err
+		}
+	}
+	return len(dropExpired(entries, window)), nil
+}
+
+func dropExpired(entries []Entry, ttl time.Duration) []Entry {
+	if ttl <= 0 {
+		return entries
+	}
+	cutoff := time.Now().Add(-ttl)
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.Timestamp.After(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
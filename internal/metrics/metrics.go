@@ -0,0 +1,136 @@
+// Package metrics defines the Prometheus metrics grim-reaper exposes about
+// its own activity.
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	// EvictionRetriesTotal counts eviction attempts that had to be retried,
+	// labeled by the backoff policy in effect.
+	EvictionRetriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grim_reaper",
+		Name:      "eviction_retries_total",
+		Help:      "Number of pod eviction attempts that were retried after a failure.",
+	}, []string{"policy"})
+
+	// EvictionBackoffSeconds observes how long the evictor slept between
+	// retries, labeled by the backoff policy in effect.
+	EvictionBackoffSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "grim_reaper",
+		Name:      "eviction_backoff_seconds",
+		Help:      "Delay slept between eviction retries.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"policy"})
+
+	// ObserverSimulatedReapableNodes is the number of nodes the selection
+	// pipeline would reap this cycle, as seen by observer mode.
+	ObserverSimulatedReapableNodes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "grim_reaper",
+		Name:      "observer_simulated_reapable_nodes",
+		Help:      "Nodes the selection pipeline would reap this cycle, per observer mode.",
+	})
+
+	// ObserverSimulatedDrainBlockedPods counts, per node, how many pods a
+	// dry-run eviction reports as currently blocked (typically by a PDB).
+	ObserverSimulatedDrainBlockedPods = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "grim_reaper",
+		Name:      "observer_simulated_drain_blocked_pods",
+		Help:      "Pods a dry-run eviction reports as currently blocked, per candidate node.",
+	}, []string{"node"})
+
+	// NodesReapedTotal counts nodes successfully harvested, labeled by the
+	// ReapReason selection tagged them with, so an operator can tell at a
+	// glance whether reaping is mostly routine cycling or mostly forced
+	// criteria like TTL or image rollout.
+	NodesReapedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grim_reaper",
+		Name:      "nodes_reaped_total",
+		Help:      "Nodes successfully harvested, labeled by reap reason.",
+	}, []string{"reason"})
+
+	// SchedulerBackpressureActive is 1 while a run is paused waiting for
+	// Pending pods to clear MaxPendingPods/MaxPendingPodAge, 0 otherwise.
+	SchedulerBackpressureActive = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "grim_reaper",
+		Name:      "scheduler_backpressure_active",
+		Help:      "1 while a run is paused waiting for the scheduler's Pending pod backlog to clear, 0 otherwise.",
+	})
+
+	// BackpressurePauseSeconds observes how long each back-pressure pause
+	// lasted.
+	BackpressurePauseSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "grim_reaper",
+		Name:      "backpressure_pause_seconds",
+		Help:      "Duration of each pause taken to let the scheduler's Pending pod backlog clear.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// TerminationsPending is the number of instance terminations the
+	// async Terminator is currently waiting on or retrying, so an
+	// operator can tell whether a slow cloud API is backing up behind
+	// it.
+	TerminationsPending = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "grim_reaper",
+		Name:      "terminations_pending",
+		Help:      "Instance terminations the async terminator is currently waiting on or retrying.",
+	})
+
+	// TerminationRetriesTotal counts instance termination attempts that
+	// had to be retried, labeled by the backoff policy in effect.
+	TerminationRetriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grim_reaper",
+		Name:      "termination_retries_total",
+		Help:      "Number of instance termination attempts that were retried after a failure.",
+	}, []string{"policy"})
+
+	// TerminationsAbandonedTotal counts instance terminations the
+	// terminator gave up retrying, after exhausting its retry budget.
+	TerminationsAbandonedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "grim_reaper",
+		Name:      "terminations_abandoned_total",
+		Help:      "Instance terminations abandoned after exhausting the terminator's retry budget.",
+	})
+
+	// PodTerminationSeconds observes how long a pod actually took to
+	// disappear from the API server after its eviction was accepted, as
+	// tracked by the shared per-node deletion watch.
+	PodTerminationSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "grim_reaper",
+		Name:      "pod_termination_seconds",
+		Help:      "Time between an eviction being accepted and the pod actually disappearing from the API server.",
+		Buckets:   prometheus.DefBuckets,
+	})
+)
+
+// Def describes one registered metric well enough to render a dashboard
+// panel or alert rule for it, alongside the prometheus.Collector itself.
+type Def struct {
+	Collector prometheus.Collector
+	Name      string
+	Help      string
+	Type      string // counter, gauge, or histogram
+}
+
+// Defs is every metric grim-reaper registers. It's the single source of
+// truth both for registration below and for internal/dashboards, so a
+// dashboard or alert rule can never drift out of sync with what's actually
+// exported -- adding a metric here is enough to pick it up in both places.
+var Defs = []Def{
+	{Collector: EvictionRetriesTotal, Name: "grim_reaper_eviction_retries_total", Help: "Number of pod eviction attempts that were retried after a failure.", Type: "counter"},
+	{Collector: EvictionBackoffSeconds, Name: "grim_reaper_eviction_backoff_seconds", Help: "Delay slept between eviction retries.", Type: "histogram"},
+	{Collector: ObserverSimulatedReapableNodes, Name: "grim_reaper_observer_simulated_reapable_nodes", Help: "Nodes the selection pipeline would reap this cycle, per observer mode.", Type: "gauge"},
+	{Collector: ObserverSimulatedDrainBlockedPods, Name: "grim_reaper_observer_simulated_drain_blocked_pods", Help: "Pods a dry-run eviction reports as currently blocked, per candidate node.", Type: "gauge"},
+	{Collector: NodesReapedTotal, Name: "grim_reaper_nodes_reaped_total", Help: "Nodes successfully harvested, labeled by reap reason.", Type: "counter"},
+	{Collector: SchedulerBackpressureActive, Name: "grim_reaper_scheduler_backpressure_active", Help: "1 while a run is paused waiting for the scheduler's Pending pod backlog to clear, 0 otherwise.", Type: "gauge"},
+	{Collector: BackpressurePauseSeconds, Name: "grim_reaper_backpressure_pause_seconds", Help: "Duration of each pause taken to let the scheduler's Pending pod backlog clear.", Type: "histogram"},
+	{Collector: TerminationsPending, Name: "grim_reaper_terminations_pending", Help: "Instance terminations the async terminator is currently waiting on or retrying.", Type: "gauge"},
+	{Collector: TerminationRetriesTotal, Name: "grim_reaper_termination_retries_total", Help: "Number of instance termination attempts that were retried after a failure.", Type: "counter"},
+	{Collector: TerminationsAbandonedTotal, Name: "grim_reaper_terminations_abandoned_total", Help: "Instance terminations abandoned after exhausting the terminator's retry budget.", Type: "counter"},
+	{Collector: PodTerminationSeconds, Name: "grim_reaper_pod_termination_seconds", Help: "Time between an eviction being accepted and the pod actually disappearing from the API server.", Type: "histogram"},
+}
+
+func init() {
+	for _, d := range Defs {
+		prometheus.MustRegister(d.Collector)
+	}
+}
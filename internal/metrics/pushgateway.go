@@ -0,0 +1,14 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus/push"
+
+// PushToGateway pushes every registered metric to a Prometheus Pushgateway
+// at url under jobName. grim-reaper runs as a short-lived job, so without
+// this a scrape can easily miss an entire run's metrics.
+func PushToGateway(url, jobName string) error {
+	pusher := push.New(url, jobName)
+	for _, d := range Defs {
+		pusher = pusher.Collector(d.Collector)
+	}
+	return pusher.Push()
+}
@@ -0,0 +1,44 @@
+// Package notify sends human-readable notifications about reaper activity
+// to chat platforms.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Notifier delivers a message to whatever channel it's configured for.
+type Notifier interface {
+	Notify(message string) error
+}
+
+// SlackNotifier posts messages to a Slack incoming webhook.
+type SlackNotifier struct {
+	WebhookURL string
+	Client     *http.Client
+}
+
+// NewSlackNotifier returns a Notifier that posts to webhookURL.
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{WebhookURL: webhookURL, Client: http.DefaultClient}
+}
+
+func (s *SlackNotifier) Notify(message string) error {
+	body, err := json.Marshal(map[string]string{"text": message})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.Client.Post(s.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
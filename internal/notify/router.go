@@ -0,0 +1,45 @@
+package notify
+
+// NamespaceOwnerAnnotation is the namespace annotation that, when present,
+// names an additional webhook to notify about pod evictions in that
+// namespace.
+const NamespaceOwnerAnnotation = "grim-reaper.io/notify-webhook"
+
+// Router fans a message out to the platform-wide notifier plus whichever
+// per-namespace notifier owns the affected workload, so teams learn about
+// disruption to their own pods without everyone being on the platform
+// channel.
+type Router struct {
+	// Platform is always notified, regardless of namespace.
+	Platform Notifier
+
+	// ByNamespace maps a namespace name to the notifier for its owning
+	// team, populated from static config and namespace annotations.
+	ByNamespace map[string]Notifier
+}
+
+// NewRouter returns a Router that always notifies platform, plus any
+// namespace-specific notifiers registered in byNamespace.
+func NewRouter(platform Notifier, byNamespace map[string]Notifier) *Router {
+	if byNamespace == nil {
+		byNamespace = map[string]Notifier{}
+	}
+	return &Router{Platform: platform, ByNamespace: byNamespace}
+}
+
+// NotifyEviction delivers message to the platform notifier and, if
+// namespace has an owning team registered, to that team as well.
+func (r *Router) NotifyEviction(namespace, message string) error {
+	var firstErr error
+	if r.Platform != nil {
+		if err := r.Platform.Notify(message); err != nil {
+			firstErr = err
+		}
+	}
+	if owner, ok := r.ByNamespace[namespace]; ok {
+		if err := owner.Notify(message); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,126 @@
+// Package preflight checks, via SelfSubjectAccessReview, that grim-reaper's
+// credentials actually grant every permission its enabled features need --
+// so a missing RBAC rule is caught at startup with a clear list, instead of
+// surfacing as a generic Forbidden error midway through a drain.
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/BrianKopp/grim-reaper/internal/config"
+)
+
+// check is a single permission to verify via SelfSubjectAccessReview.
+type check struct {
+	verb        string
+	group       string
+	resource    string
+	subresource string
+	namespace   string
+}
+
+// Check performs a SelfSubjectAccessReview for every verb/resource
+// grim-reaper's enabled features need, given cfg, and returns an error
+// listing every one that's denied. A nil result means every check passed.
+func Check(clientset kubernetes.Interface, cfg *config.Config) error {
+	checks := requiredChecks(cfg)
+
+	var denied []string
+	for _, c := range checks {
+		allowed, err := c.allowed(clientset)
+		if err != nil {
+			return fmt.Errorf("checking permission for %s: %w", c.describe(), err)
+		}
+		if !allowed {
+			denied = append(denied, c.describe())
+		}
+	}
+	if len(denied) > 0 {
+		return fmt.Errorf("missing required RBAC permissions: %s", strings.Join(denied, "; "))
+	}
+	return nil
+}
+
+// requiredChecks returns the permission checks needed by the features cfg
+// actually enables. Node and pod access is always required; everything else
+// is conditional on the feature that needs it.
+func requiredChecks(cfg *config.Config) []check {
+	checks := []check{
+		{verb: "list", resource: "nodes"},
+		{verb: "patch", resource: "nodes"},
+		{verb: "delete", resource: "nodes"},
+		{verb: "list", resource: "pods"},
+		{verb: "delete", resource: "pods"},
+	}
+
+	if cfg.DrainBackend == "kubectl" {
+		checks = append(checks, check{verb: "get", resource: "pods", subresource: "eviction"})
+	}
+	checks = append(checks, check{verb: "create", resource: "pods", subresource: "eviction"})
+
+	if cfg.PassoverLedgerEnabled {
+		checks = append(checks,
+			check{verb: "get", resource: "configmaps", namespace: cfg.LeaseNamespace},
+			check{verb: "create", resource: "configmaps", namespace: cfg.LeaseNamespace},
+			check{verb: "update", resource: "configmaps", namespace: cfg.LeaseNamespace},
+		)
+	}
+
+	if cfg.SelfNodeName == "" && cfg.PodName != "" && cfg.PodNamespace != "" {
+		checks = append(checks, check{verb: "get", resource: "pods", namespace: cfg.PodNamespace})
+	}
+
+	if cfg.CollectEventCorrelation {
+		checks = append(checks, check{verb: "list", resource: "events"})
+	}
+
+	if cfg.LeaderElection {
+		checks = append(checks,
+			check{verb: "get", group: "coordination.k8s.io", resource: "leases", namespace: cfg.LeaseNamespace},
+			check{verb: "create", group: "coordination.k8s.io", resource: "leases", namespace: cfg.LeaseNamespace},
+			check{verb: "update", group: "coordination.k8s.io", resource: "leases", namespace: cfg.LeaseNamespace},
+		)
+	}
+
+	return checks
+}
+
+func (c check) allowed(clientset kubernetes.Interface) (bool, error) {
+	review := &authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Namespace:   c.namespace,
+				Verb:        c.verb,
+				Group:       c.group,
+				Resource:    c.resource,
+				Subresource: c.subresource,
+			},
+		},
+	}
+	result, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(context.Background(), review, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return result.Status.Allowed, nil
+}
+
+// describe renders c as a short human-readable string for error messages.
+func (c check) describe() string {
+	resource := c.resource
+	if c.subresource != "" {
+		resource = resource + "/" + c.subresource
+	}
+	if c.group != "" {
+		resource = c.group + "/" + resource
+	}
+	if c.namespace != "" {
+		return fmt.Sprintf("%s %s in namespace %s", c.verb, resource, c.namespace)
+	}
+	return fmt.Sprintf("%s %s", c.verb, resource)
+}
@@ -0,0 +1,72 @@
+package reaper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// prometheusAlertGate is the default AlertGate, backed by a Prometheus
+// (or Thanos/Mimir-compatible) instant-query endpoint.
+type prometheusAlertGate struct {
+	baseURL string
+	query   string
+	client  *http.Client
+}
+
+// NewPrometheusAlertGate returns an AlertGate that runs query (typically
+// `ALERTS{alertstate="firing"}`) against the Prometheus HTTP API at
+// baseURL to list currently-firing alerts.
+func NewPrometheusAlertGate(baseURL, query string) AlertGate {
+	return &prometheusAlertGate{baseURL: baseURL, query: query, client: http.DefaultClient}
+}
+
+// prometheusQueryResponse covers just the fields FiringAlerts needs from
+// a Prometheus /api/v1/query response.
+type prometheusQueryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+		} `json:"result"`
+	} `json:"data"`
+	Error string `json:"error"`
+}
+
+func (g *prometheusAlertGate) FiringAlerts(ctx context.Context) ([]string, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/query?query=%s", g.baseURL, url.QueryEscape(g.query))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("prometheus query returned status %d", resp.StatusCode)
+	}
+
+	var parsed prometheusQueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("decoding prometheus query response: %w", err)
+	}
+	if parsed.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", parsed.Error)
+	}
+
+	alerts := make([]string, 0, len(parsed.Data.Result))
+	for _, series := range parsed.Data.Result {
+		name := series.Metric["alertname"]
+		if name == "" {
+			continue
+		}
+		alerts = append(alerts, name)
+	}
+	return alerts, nil
+}
@@ -0,0 +1,103 @@
+package reaper
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// BackoffPolicy computes how long to wait before retrying attempt (the
+// attempt number, starting at 1).
+type BackoffPolicy interface {
+	Name() string
+	NextDelay(attempt int) time.Duration
+}
+
+// ConstantBackoff always waits the same delay.
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+func (c ConstantBackoff) Name() string { return "constant" }
+
+func (c ConstantBackoff) NextDelay(attempt int) time.Duration { return c.Delay }
+
+// ExponentialBackoff doubles Base every attempt, up to Max.
+type ExponentialBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+func (e ExponentialBackoff) Name() string { return "exponential" }
+
+func (e ExponentialBackoff) NextDelay(attempt int) time.Duration {
+	delay := time.Duration(float64(e.Base) * math.Pow(2, float64(attempt-1)))
+	if e.Max > 0 && delay > e.Max {
+		delay = e.Max
+	}
+	return delay
+}
+
+// DecorrelatedJitterBackoff implements the "decorrelated jitter" strategy
+// from the AWS architecture blog: each delay is a random value between Base
+// and 3x the previous delay, capped at Max. It spreads out retries from
+// many clients far better than a shared exponential curve.
+type DecorrelatedJitterBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+
+	prev time.Duration
+}
+
+func (d *DecorrelatedJitterBackoff) Name() string { return "decorrelated-jitter" }
+
+func (d *DecorrelatedJitterBackoff) NextDelay(attempt int) time.Duration {
+	ceiling := d.prev*3 + 1
+	if ceiling < d.Base {
+		ceiling = d.Base
+	}
+	delay := d.Base + time.Duration(rand.Int63n(int64(ceiling-d.Base+1)))
+	if d.Max > 0 && delay > d.Max {
+		delay = d.Max
+	}
+	d.prev = delay
+	return delay
+}
+
+// JitteredExponentialBackoff doubles Base every attempt like
+// ExponentialBackoff, then returns a random delay somewhere between zero
+// and that ceiling (full jitter), so many pods retrying against the same
+// PodDisruptionBudget don't all wake up and retry in lockstep.
+type JitteredExponentialBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+func (j JitteredExponentialBackoff) Name() string { return "jittered-exponential" }
+
+func (j JitteredExponentialBackoff) NextDelay(attempt int) time.Duration {
+	ceiling := time.Duration(float64(j.Base) * math.Pow(2, float64(attempt-1)))
+	if j.Max > 0 && ceiling > j.Max {
+		ceiling = j.Max
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)))
+}
+
+// NewBackoffPolicy constructs the named policy ("constant", "exponential",
+// "decorrelated-jitter", or "jittered-exponential") with the given base
+// and max delay.
+func NewBackoffPolicy(name string, base, max time.Duration) BackoffPolicy {
+	switch name {
+	case "exponential":
+		return ExponentialBackoff{Base: base, Max: max}
+	case "decorrelated-jitter":
+		return &DecorrelatedJitterBackoff{Base: base, Max: max}
+	case "jittered-exponential":
+		return JitteredExponentialBackoff{Base: base, Max: max}
+	default:
+		return ConstantBackoff{Delay: base}
+	}
+}
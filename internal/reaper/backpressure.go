@@ -0,0 +1,81 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/BrianKopp/grim-reaper/internal/metrics"
+)
+
+// BackpressurePausedAnnotation records, on the node Run is about to
+// harvest next, why a back-pressure pause is currently in effect. Cleared
+// as soon as the pause ends.
+const BackpressurePausedAnnotation = "grim-reaper.io/backpressure-paused"
+
+// DefaultBackpressureCheckInterval is used when BackpressureCheckInterval
+// is unset but a back-pressure threshold is configured.
+const DefaultBackpressureCheckInterval = 15 * time.Second
+
+// awaitSchedulerCapacity blocks before harvesting node while the cluster
+// looks overloaded -- more than MaxPendingPods pods Pending, or the oldest
+// Pending pod older than MaxPendingPodAge -- rechecking every
+// BackpressureCheckInterval until it clears or BackpressureTimeout elapses
+// (0 waits indefinitely). It's a no-op if neither threshold is configured.
+func (g *GrimReaper) awaitSchedulerCapacity(ctx context.Context, node *corev1.Node) {
+	if g.Config.MaxPendingPods <= 0 && g.Config.MaxPendingPodAge <= 0 {
+		return
+	}
+
+	interval := g.Config.BackpressureCheckInterval
+	if interval <= 0 {
+		interval = DefaultBackpressureCheckInterval
+	}
+
+	start := time.Now()
+	paused := false
+	for {
+		pending, err := g.Pods.ListPendingPods(ctx)
+		if err != nil {
+			log.Printf("backpressure: listing pending pods: %v", err)
+			break
+		}
+
+		count := len(pending)
+		oldest := oldestPendingAge(pending, time.Now())
+		overCount := g.Config.MaxPendingPods > 0 && count > g.Config.MaxPendingPods
+		overAge := g.Config.MaxPendingPodAge > 0 && oldest > g.Config.MaxPendingPodAge
+		if !overCount && !overAge {
+			break
+		}
+
+		if !paused {
+			paused = true
+			metrics.SchedulerBackpressureActive.Set(1)
+			message := fmt.Sprintf("waiting for the scheduler's pending backlog to clear (%d pod(s) pending, oldest waiting %s)", count, oldest.Round(time.Second))
+			if err := g.Nodes.Annotate(ctx, node, map[string]interface{}{BackpressurePausedAnnotation: message}); err != nil {
+				log.Printf("backpressure: annotating node %s: %v", node.Name, err)
+			}
+		}
+
+		if g.Config.BackpressureTimeout > 0 && time.Since(start) >= g.Config.BackpressureTimeout {
+			log.Printf("backpressure: gave up waiting for the scheduler's pending backlog to clear after %s, harvesting node %s anyway", g.Config.BackpressureTimeout, node.Name)
+			break
+		}
+
+		log.Printf("backpressure: %d pod(s) pending (oldest waiting %s), pausing before harvesting node %s", count, oldest.Round(time.Second), node.Name)
+		time.Sleep(interval)
+	}
+
+	if !paused {
+		return
+	}
+	metrics.SchedulerBackpressureActive.Set(0)
+	metrics.BackpressurePauseSeconds.Observe(time.Since(start).Seconds())
+	if err := g.Nodes.Annotate(ctx, node, map[string]interface{}{BackpressurePausedAnnotation: nil}); err != nil {
+		log.Printf("backpressure: clearing pause annotation on node %s: %v", node.Name, err)
+	}
+}
@@ -0,0 +1,111 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// runCanary harvests exactly one node and, once that succeeds, watches the
+// cluster for Config.CanaryVerificationPeriod before Run is allowed to
+// dispatch the rest of the batch. Returns the canary's NodeReapReport
+// alongside an error from either the harvest itself or the verification
+// window -- either one means Run should stop without touching the
+// remaining nodes.
+func (g *GrimReaper) runCanary(ctx context.Context, node *corev1.Node) (NodeReapReport, error) {
+	pods, err := g.Pods.ListPodsOnNode(ctx, node.Name)
+	if err != nil {
+		log.Printf("canary: listing pods on node %s: %v", node.Name, err)
+	}
+	evictedPods := len(pods)
+	statefulBytes := g.nodeStatefulBytes(ctx, pods)
+
+	result, harvestErr := g.runOneNode(ctx, node, pods, evictedPods, statefulBytes)
+	if harvestErr != nil {
+		return result, fmt.Errorf("canary: %w", harvestErr)
+	}
+
+	if err := g.awaitCanaryVerification(ctx, node, pods); err != nil {
+		return result, fmt.Errorf("canary: %w", err)
+	}
+	return result, nil
+}
+
+// awaitCanaryVerification polls, every BackpressureCheckInterval, for
+// Config.CanaryVerificationPeriod (skipped entirely if zero) after a
+// canary harvest, checking that node's evicted pods' replacements aren't
+// stuck Pending and that every selector-matching node still left in the
+// cluster is Ready. Returns the first problem found, or nil once the
+// period elapses without one.
+func (g *GrimReaper) awaitCanaryVerification(ctx context.Context, node *corev1.Node, evictedPods []corev1.Pod) error {
+	if g.Config.CanaryVerificationPeriod <= 0 {
+		return nil
+	}
+
+	owners := map[string]bool{}
+	for i := range evictedPods {
+		if key := podOwnerKey(&evictedPods[i]); key != "" {
+			owners[key] = true
+		}
+	}
+
+	interval := g.Config.BackpressureCheckInterval
+	if interval <= 0 {
+		interval = DefaultBackpressureCheckInterval
+	}
+
+	deadline := time.Now().Add(g.Config.CanaryVerificationPeriod)
+	for {
+		if err := g.checkCanaryHealth(ctx, node, owners); err != nil {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return nil
+		}
+
+		log.Printf("canary: watching node %s's replacement pods and cluster readiness for %s", node.Name, g.Config.CanaryVerificationPeriod)
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// checkCanaryHealth is a single pass of awaitCanaryVerification's checks:
+// no pod displaced from the canary node stuck Pending past
+// VerifyPendingPodGrace, and no selector-matching node gone NotReady.
+func (g *GrimReaper) checkCanaryHealth(ctx context.Context, node *corev1.Node, owners map[string]bool) error {
+	if g.Config.VerifyPendingPodGrace > 0 && len(owners) > 0 {
+		pending, err := g.Pods.ListPendingPods(ctx)
+		if err != nil {
+			log.Printf("canary: listing pending pods: %v", err)
+		} else {
+			now := time.Now()
+			for i := range pending {
+				key := podOwnerKey(&pending[i])
+				if key == "" || !owners[key] {
+					continue
+				}
+				if age := now.Sub(pending[i].CreationTimestamp.Time); age > g.Config.VerifyPendingPodGrace {
+					return fmt.Errorf("pod %s/%s, displaced from canary node %s, has been Pending for %s", pending[i].Namespace, pending[i].Name, node.Name, age.Round(time.Second))
+				}
+			}
+		}
+	}
+
+	nodes, err := g.Nodes.ListNodes(ctx, g.Config.NodeSelector)
+	if err != nil {
+		log.Printf("canary: listing nodes: %v", err)
+		return nil
+	}
+	for i := range nodes {
+		if !nodeIsReady(nodes[i]) {
+			return fmt.Errorf("node %s is not Ready during canary verification", nodes[i].Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package reaper
+
+import (
+	"context"
+	"log"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PassoverReasonCapacityHeadroom is recorded against nodes that were
+// otherwise selected for reaping, but removing them wouldn't leave the
+// rest of the cluster with enough allocatable headroom -- plus
+// Config.CapacityHeadroomMargin -- to absorb the pods they'd displace.
+const PassoverReasonCapacityHeadroom = "capacity-headroom"
+
+// filterCapacityHeadroom re-checks selected, in order, against the
+// cluster's real allocatable capacity, turning MinNodes from a plain node
+// count into an actual capacity guard: allNodes' total allocatable
+// CPU/memory is the starting pool, and each candidate both removes its
+// own allocatable from that pool and adds its pods' requests to what the
+// other, not-yet-removed nodes would need to absorb. A candidate that
+// wouldn't fit -- with Config.CapacityHeadroomMargin applied as a safety
+// buffer on top of the bare requests -- is passed over instead, leaving
+// its capacity in the pool for later candidates. No-op if
+// Config.CapacityHeadroomCheck is false.
+func (g *GrimReaper) filterCapacityHeadroom(ctx context.Context, allNodes, selected []corev1.Node) []corev1.Node {
+	if !g.Config.CapacityHeadroomCheck {
+		return selected
+	}
+
+	var poolCPU, poolMem int64
+	for i := range allNodes {
+		poolCPU += allNodes[i].Status.Allocatable.Cpu().MilliValue()
+		poolMem += allNodes[i].Status.Allocatable.Memory().Value()
+	}
+
+	var kept, passedOver []corev1.Node
+	var absorbCPU, absorbMem int64
+	margin := 1 + g.Config.CapacityHeadroomMargin
+	for i := range selected {
+		node := &selected[i]
+		nodeCPU := node.Status.Allocatable.Cpu().MilliValue()
+		nodeMem := node.Status.Allocatable.Memory().Value()
+
+		pods, err := g.Pods.ListPodsOnNode(ctx, node.Name)
+		if err != nil {
+			log.Printf("capacity headroom check: listing pods on node %s: %v", node.Name, err)
+			kept = append(kept, *node)
+			poolCPU -= nodeCPU
+			poolMem -= nodeMem
+			continue
+		}
+		displacedCPU, displacedMem := podRequests(pods)
+		needCPU := absorbCPU + displacedCPU
+		needMem := absorbMem + displacedMem
+
+		remainingCPU := poolCPU - nodeCPU
+		remainingMem := poolMem - nodeMem
+
+		if float64(remainingCPU) < float64(needCPU)*margin || float64(remainingMem) < float64(needMem)*margin {
+			log.Printf("node %s would displace %dm CPU / %d byte(s) memory; the rest of the cluster doesn't have %.0f%% headroom to absorb that, skipping", node.Name, displacedCPU, displacedMem, g.Config.CapacityHeadroomMargin*100)
+			passedOver = append(passedOver, *node)
+			continue
+		}
+
+		kept = append(kept, *node)
+		poolCPU = remainingCPU
+		poolMem = remainingMem
+		absorbCPU = needCPU
+		absorbMem = needMem
+	}
+
+	g.recordPassovers(passedOver, PassoverReasonCapacityHeadroom)
+	return kept
+}
+
+// podRequests sums the CPU (in millicores) and memory (in bytes) requests
+// of every container across pods.
+func podRequests(pods []corev1.Pod) (cpuMilli, memBytes int64) {
+	for i := range pods {
+		for _, c := range pods[i].Spec.Containers {
+			cpuMilli += c.Resources.Requests.Cpu().MilliValue()
+			memBytes += c.Resources.Requests.Memory().Value()
+		}
+	}
+	return cpuMilli, memBytes
+}
@@ -0,0 +1,73 @@
+package reaper
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// DetectEvictionAPIVersion queries the server's discovery document to pick
+// the best available Eviction subresource version: "v1" (policy/v1, the
+// only option left on Kubernetes 1.25+), falling back to "v1beta1" for
+// older servers. It errors out if the server supports neither, rather than
+// letting a caller discover that mid-drain as a deprecated-API failure.
+func DetectEvictionAPIVersion(clientset kubernetes.Interface) (string, error) {
+	if supportsResource(clientset, "policy/v1", "pods/eviction") {
+		return "v1", nil
+	}
+	if supportsResource(clientset, "policy/v1beta1", "pods/eviction") {
+		return "v1beta1", nil
+	}
+	return "", fmt.Errorf("server supports neither policy/v1 nor policy/v1beta1 pods/eviction")
+}
+
+// checkLeaseAPI confirms the server supports coordination.k8s.io/v1 leases,
+// which grim-reaper's leader election depends on.
+func checkLeaseAPI(clientset kubernetes.Interface) error {
+	if supportsResource(clientset, "coordination.k8s.io/v1", "leases") {
+		return nil
+	}
+	return fmt.Errorf("server does not support coordination.k8s.io/v1 leases, required for --leader-election")
+}
+
+// supportsResource reports whether the server's discovery document lists
+// resource (e.g. "pods/eviction") under groupVersion (e.g. "policy/v1"). A
+// discovery error is treated as unsupported, matching the conservative
+// "refuse to run" stance callers want from a compatibility check.
+func supportsResource(clientset kubernetes.Interface, groupVersion, resource string) bool {
+	resources, err := clientset.Discovery().ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		return false
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckServerCompatibility queries the API server's version and the
+// feature availability its enabled options need (the eviction subresource,
+// and leases if leader election is on), returning the eviction API version
+// to use. It errors out with a clear message instead of letting grim-reaper
+// start and fail on a deprecated or missing group/version call later.
+func CheckServerCompatibility(clientset kubernetes.Interface, leaderElectionEnabled bool) (evictionAPIVersion string, err error) {
+	version, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		return "", fmt.Errorf("querying server version: %w", err)
+	}
+
+	evictionAPIVersion, err = DetectEvictionAPIVersion(clientset)
+	if err != nil {
+		return "", fmt.Errorf("kubernetes %s: %w", version.String(), err)
+	}
+
+	if leaderElectionEnabled {
+		if err := checkLeaseAPI(clientset); err != nil {
+			return "", fmt.Errorf("kubernetes %s: %w", version.String(), err)
+		}
+	}
+
+	return evictionAPIVersion, nil
+}
@@ -0,0 +1,79 @@
+package reaper
+
+import (
+	"context"
+	"log"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// awaitNodeCooldown pauses after node has been successfully harvested, per
+// --node-cooldown, giving the scheduler and autoscaler time to absorb the
+// pods it displaced before the next node starts. With
+// Config.NodeCooldownWaitForPodsRunning unset, it's a fixed sleep; with it
+// set, it instead polls until none of evictedPods' owners still have a
+// pod Pending, capped at NodeCooldown so a workload that never recovers
+// doesn't stall the run forever. A zero NodeCooldown disables the pause
+// entirely.
+func (g *GrimReaper) awaitNodeCooldown(ctx context.Context, node *corev1.Node, evictedPods []corev1.Pod) {
+	if g.Config.NodeCooldown <= 0 {
+		return
+	}
+
+	if !g.Config.NodeCooldownWaitForPodsRunning {
+		log.Printf("node %s harvested; cooling down for %s before the next harvest", node.Name, g.Config.NodeCooldown)
+		select {
+		case <-time.After(g.Config.NodeCooldown):
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	owners := map[string]bool{}
+	for i := range evictedPods {
+		if key := podOwnerKey(&evictedPods[i]); key != "" {
+			owners[key] = true
+		}
+	}
+	if len(owners) == 0 {
+		return
+	}
+
+	interval := g.Config.BackpressureCheckInterval
+	if interval <= 0 {
+		interval = DefaultBackpressureCheckInterval
+	}
+
+	start := time.Now()
+	for {
+		pending, err := g.Pods.ListPendingPods(ctx)
+		if err != nil {
+			log.Printf("node cooldown: listing pending pods: %v", err)
+			return
+		}
+
+		stillPending := false
+		for i := range pending {
+			if owners[podOwnerKey(&pending[i])] {
+				stillPending = true
+				break
+			}
+		}
+		if !stillPending {
+			return
+		}
+
+		if time.Since(start) >= g.Config.NodeCooldown {
+			log.Printf("node cooldown: gave up waiting for node %s's displaced pods to become Running after %s", node.Name, g.Config.NodeCooldown)
+			return
+		}
+
+		log.Printf("node cooldown: waiting for node %s's displaced pods to become Running", node.Name)
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package reaper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ErrPodDeletionTimeout is returned when a pod's actual deletion isn't
+// observed within the configured deletion timeout after its eviction was
+// accepted, so a caller can distinguish a stuck termination from every
+// other kind of drain failure.
+var ErrPodDeletionTimeout = fmt.Errorf("timed out waiting for pod deletion")
+
+// errDeletionWatchClosed is returned by podDeletionTracker.WaitFor when the
+// underlying watch ended (e.g. the API server closed the connection) before
+// pod's deletion was observed.
+var errDeletionWatchClosed = fmt.Errorf("pod deletion watch closed")
+
+// podDeletionTracker watches every pod on a node through a single shared
+// watch connection and lets concurrently evicted pods each wait for their
+// own deletion, so draining a node with hundreds of pods opens one watch
+// instead of hammering the API server with a Get per pod.
+type podDeletionTracker struct {
+	watcher watch.Interface
+
+	mu      sync.Mutex
+	waiters map[string][]chan time.Time
+	done    map[string]time.Time
+	closed  bool
+}
+
+// newPodDeletionTracker starts consuming watcher's events in the
+// background. Callers must call Stop when finished with it.
+func newPodDeletionTracker(watcher watch.Interface) *podDeletionTracker {
+	t := &podDeletionTracker{
+		watcher: watcher,
+		waiters: map[string][]chan time.Time{},
+		done:    map[string]time.Time{},
+	}
+	go t.run()
+	return t
+}
+
+func podDeletionKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func (t *podDeletionTracker) run() {
+	for event := range t.watcher.ResultChan() {
+		if event.Type != watch.Deleted {
+			continue
+		}
+		pod, ok := event.Object.(*corev1.Pod)
+		if !ok {
+			continue
+		}
+		t.markDeleted(podDeletionKey(pod.Namespace, pod.Name), time.Now())
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	for key, waiters := range t.waiters {
+		for _, ch := range waiters {
+			close(ch)
+		}
+		delete(t.waiters, key)
+	}
+}
+
+func (t *podDeletionTracker) markDeleted(key string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.done[key] = at
+	for _, ch := range t.waiters[key] {
+		ch <- at
+	}
+	delete(t.waiters, key)
+}
+
+// WaitFor blocks until pod's deletion is observed through the shared
+// watch, ctx is canceled, or timeout elapses, whichever comes first, and
+// returns how long the wait took. It's safe to call concurrently for
+// different pods tracked by the same watch.
+func (t *podDeletionTracker) WaitFor(ctx context.Context, pod *corev1.Pod, timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	key := podDeletionKey(pod.Namespace, pod.Name)
+
+	t.mu.Lock()
+	if at, ok := t.done[key]; ok {
+		t.mu.Unlock()
+		return at.Sub(start), nil
+	}
+	if t.closed {
+		t.mu.Unlock()
+		return 0, fmt.Errorf("pod %s: %w", key, errDeletionWatchClosed)
+	}
+	ch := make(chan time.Time, 1)
+	t.waiters[key] = append(t.waiters[key], ch)
+	t.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	select {
+	case at, ok := <-ch:
+		if !ok {
+			return 0, fmt.Errorf("pod %s: %w", key, errDeletionWatchClosed)
+		}
+		return at.Sub(start), nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return 0, fmt.Errorf("pod %s: %w", key, ErrPodDeletionTimeout)
+		}
+		return 0, ctx.Err()
+	}
+}
+
+// Stop ends the underlying watch. Safe to call even if the watch has
+// already closed on its own.
+func (t *podDeletionTracker) Stop() {
+	t.watcher.Stop()
+}
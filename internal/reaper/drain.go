@@ -0,0 +1,528 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/BrianKopp/grim-reaper/internal/metrics"
+	"github.com/BrianKopp/grim-reaper/internal/notify"
+	"github.com/BrianKopp/grim-reaper/internal/schedule"
+)
+
+// defaultDeletionTimeout bounds how long the shared per-node watch waits
+// for each evicted pod to actually disappear, when customDrainer's
+// deletionTimeout is unset.
+const defaultDeletionTimeout = 2 * time.Minute
+
+// Drainer evicts every evictable pod from a node and reports how many pods
+// were evicted per namespace.
+type Drainer interface {
+	Drain(ctx context.Context, node *corev1.Node) (evictedByNamespace map[string]int, err error)
+}
+
+// EvictionOrderAnnotation lets a pod declare when, relative to its
+// neighbors on the same node, it should be evicted. Pods are evicted in
+// ascending order of this value, so a mesh sidecar-dependent app pod (order
+// "0") is evicted before the shared proxy it depends on (order "10").
+// Pods without the annotation default to 0. Among pods with the same
+// order, stateless pods are evicted before PVC-bearing ones, and otherwise
+// in their original listing order (see sortByEvictionOrder).
+const EvictionOrderAnnotation = "grim-reaper.io/evict-order"
+
+// BarePodPolicy controls how grim-reaper treats pods with no
+// ownerReferences, since evicting a bare pod destroys it permanently
+// instead of letting a controller reschedule it.
+type BarePodPolicy string
+
+const (
+	// BarePodSkip leaves bare pods running and continues draining the rest
+	// of the node.
+	BarePodSkip BarePodPolicy = "skip"
+	// BarePodEvict treats bare pods like any other pod.
+	BarePodEvict BarePodPolicy = "evict"
+	// BarePodPassoverNode aborts the drain for the whole node the moment a
+	// bare pod is found on it.
+	BarePodPassoverNode BarePodPolicy = "passover-node"
+	// BarePodRequireApproval leaves bare pods running and flags them for a
+	// human to review before they're ever evicted.
+	BarePodRequireApproval BarePodPolicy = "require-approval"
+)
+
+// errPassoverNode signals that a node should be skipped entirely rather
+// than partially drained.
+var errPassoverNode = fmt.Errorf("node passed over")
+
+// ClusterAutoscalerSafeToEvictAnnotation and KarpenterDoNotDisruptAnnotation
+// are the disruption-control conventions other cluster tooling already
+// uses to mark a pod as unsafe to evict. grim-reaper honors both so it
+// cooperates with whichever autoscaler is running, rather than fighting it.
+const (
+	ClusterAutoscalerSafeToEvictAnnotation = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+	KarpenterDoNotDisruptAnnotation        = "karpenter.sh/do-not-disrupt"
+)
+
+// DisruptionWindowAnnotation lets a workload declare its own allowed
+// disruption window as a standard 5-field cron expression (e.g. "0 2 * * *"
+// for "2am daily"), on top of whatever cluster-wide --schedule grim-reaper
+// is already restricted to. A pod outside its window passes over the whole
+// node, the same as a PDB violation or an unsafe-to-evict pod, so the
+// application's and the cluster's maintenance calendars are both honored
+// rather than one overriding the other.
+const DisruptionWindowAnnotation = "grim-reaper.io/disruption-window"
+
+// DisruptionWindowDurationAnnotation sets how long DisruptionWindowAnnotation's
+// window stays open after each cron match (a Go duration string, e.g.
+// "2h"). Defaults to defaultDisruptionWindowDuration if absent or invalid.
+const DisruptionWindowDurationAnnotation = "grim-reaper.io/disruption-window-duration"
+
+// defaultDisruptionWindowDuration is used when a pod sets
+// DisruptionWindowAnnotation but not DisruptionWindowDurationAnnotation.
+const defaultDisruptionWindowDuration = time.Hour
+
+// NamespaceTierAnnotation marks a namespace as belonging to a tenant tier
+// (e.g. "production-critical") that grim-reaper may only evict pods from if
+// the tier is explicitly named in AllowedTenantTiers. A namespace without
+// this annotation is unaffected.
+const NamespaceTierAnnotation = "grim-reaper.io/tier"
+
+// blocksEviction reports whether pod is marked unsafe to evict by either
+// convention ClusterAutoscalerSafeToEvictAnnotation or
+// KarpenterDoNotDisruptAnnotation recognizes.
+func blocksEviction(pod corev1.Pod) bool {
+	if v, ok := pod.Annotations[ClusterAutoscalerSafeToEvictAnnotation]; ok && v == "false" {
+		return true
+	}
+	if v, ok := pod.Annotations[KarpenterDoNotDisruptAnnotation]; ok && v == "true" {
+		return true
+	}
+	return false
+}
+
+// customDrainer is grim-reaper's own drain implementation: list pods on
+// the node, evict each one through PodEvictor.
+type customDrainer struct {
+	pods               PodEvictor
+	barePodPolicy      BarePodPolicy
+	honorSafeToEvict   bool
+	namespaces         NamespaceInterface
+	allowedTenantTiers []string
+
+	// maxConcurrentEvictions caps how many pods on the node are evicted
+	// at once. 0 or 1 evicts one at a time, as grim-reaper always has.
+	// Evictions are still issued in EvictionOrderAnnotation batches, so a
+	// higher-order group never starts before a lower-order one finishes.
+	maxConcurrentEvictions int
+
+	// maxMemoryMB, if set, is a self-imposed ceiling on the reaper
+	// process's own memory use. As usage approaches it,
+	// effectiveBatchSize degrades the eviction concurrency back down
+	// toward 1 rather than spawning more concurrent eviction goroutines
+	// into an already memory-pressured process.
+	maxMemoryMB int
+
+	// deletionTimeout bounds how long Drain waits, per pod, for an
+	// accepted eviction to actually delete the pod. Defaults to
+	// defaultDeletionTimeout if zero.
+	deletionTimeout time.Duration
+
+	// forceDeleteAfterTimeout and forceDeleteGracePeriod implement
+	// --force-delete-after-timeout: once a pod's eviction retries are
+	// exhausted, delete it directly instead of failing the whole node's
+	// drain over one stuck PDB or broken admission webhook.
+	forceDeleteAfterTimeout bool
+	forceDeleteGracePeriod  time.Duration
+
+	// notifier, if set, is told about every force-deleted pod, since
+	// bypassing the eviction API skips the PDB protections it exists to
+	// enforce and deserves loud, explicit notice.
+	notifier *notify.Router
+
+	// rateLimiter, if set, throttles how often Evict is called across the
+	// whole node, independent of maxConcurrentEvictions -- a high
+	// concurrency cap alone still lets a dense node's batch stampede the
+	// API server and its PDBs in a single burst.
+	rateLimiter *evictionRateLimiter
+}
+
+// evictionRateLimiter throttles eviction calls to at most one per interval,
+// shared across every pod on a node's drain.
+type evictionRateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newEvictionRateLimiter returns a limiter allowing perSecond eviction
+// calls per second, or nil if perSecond is 0 (no limit).
+func newEvictionRateLimiter(perSecond float64) *evictionRateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &evictionRateLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// Wait blocks until the next eviction call is allowed, or ctx is done. A
+// nil limiter never blocks.
+func (l *evictionRateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *customDrainer) Drain(ctx context.Context, node *corev1.Node) (map[string]int, error) {
+	pods, err := d.pods.ListPodsOnNode(ctx, node.Name)
+	if err != nil {
+		return nil, fmt.Errorf("listing pods: %w", err)
+	}
+	sortByEvictionOrder(pods)
+
+	tracker := d.newDeletionTracker(ctx, node.Name)
+	if tracker != nil {
+		defer tracker.Stop()
+	}
+
+	tierCache := map[string]error{}
+	toEvict := make([]corev1.Pod, 0, len(pods))
+	for i := range pods {
+		if d.honorSafeToEvict && blocksEviction(pods[i]) {
+			return nil, fmt.Errorf("node %s has pod %s/%s marked unsafe to evict: %w", node.Name, pods[i].Namespace, pods[i].Name, errPassoverNode)
+		}
+
+		if inWindow, err := inDisruptionWindow(pods[i]); err != nil {
+			log.Printf("pod %s/%s has invalid %s annotation, ignoring: %v", pods[i].Namespace, pods[i].Name, DisruptionWindowAnnotation, err)
+		} else if !inWindow {
+			return nil, fmt.Errorf("node %s has pod %s/%s outside its %s window: %w", node.Name, pods[i].Namespace, pods[i].Name, DisruptionWindowAnnotation, errPassoverNode)
+		}
+
+		if err := d.checkTenantTier(ctx, pods[i].Namespace, tierCache); err != nil {
+			return nil, fmt.Errorf("node %s has pod %s/%s: %w", node.Name, pods[i].Namespace, pods[i].Name, err)
+		}
+
+		if isDaemonSetPod(pods[i]) {
+			log.Printf("skipping DaemonSet pod %s/%s, which will simply be recreated on the same node", pods[i].Namespace, pods[i].Name)
+			continue
+		}
+
+		if len(pods[i].OwnerReferences) == 0 {
+			switch d.effectivePolicy() {
+			case BarePodSkip:
+				log.Printf("skipping bare pod %s/%s with no controller owner", pods[i].Namespace, pods[i].Name)
+				continue
+			case BarePodRequireApproval:
+				log.Printf("bare pod %s/%s with no controller owner requires manual approval before eviction; skipping", pods[i].Namespace, pods[i].Name)
+				continue
+			case BarePodPassoverNode:
+				return nil, fmt.Errorf("node %s has bare pod %s/%s with no controller owner: %w", node.Name, pods[i].Namespace, pods[i].Name, errPassoverNode)
+			}
+			// BarePodEvict falls through to the normal eviction path.
+		}
+
+		toEvict = append(toEvict, pods[i])
+	}
+
+	return d.evictBatches(ctx, toEvict, tracker)
+}
+
+// newDeletionTracker starts a shared watch over nodeName's pods for Drain
+// to track termination latency through, instead of every evicted pod
+// polling its own Get. A failure to establish the watch is logged and
+// treated as "no tracker" -- termination latency just isn't observed for
+// this drain, rather than failing the whole node over an unrelated watch
+// permission or connectivity issue.
+func (d *customDrainer) newDeletionTracker(ctx context.Context, nodeName string) *podDeletionTracker {
+	watcher, err := d.pods.WatchPodsOnNode(ctx, nodeName)
+	if err != nil {
+		log.Printf("watching pod deletions on node %s: %v; termination latency won't be tracked for this drain", nodeName, err)
+		return nil
+	}
+	return newPodDeletionTracker(watcher)
+}
+
+// effectiveDeletionTimeout returns d.deletionTimeout, or
+// defaultDeletionTimeout if unset.
+func (d *customDrainer) effectiveDeletionTimeout() time.Duration {
+	if d.deletionTimeout > 0 {
+		return d.deletionTimeout
+	}
+	return defaultDeletionTimeout
+}
+
+// awaitDeletion waits for pod's actual deletion through tracker, if one is
+// available, and observes the latency as metrics.PodTerminationSeconds. A
+// nil tracker, a timed-out wait, or a watch error is logged and otherwise
+// ignored: the eviction itself already succeeded, so a missed latency
+// observation shouldn't fail the drain.
+func (d *customDrainer) awaitDeletion(ctx context.Context, tracker *podDeletionTracker, pod *corev1.Pod) {
+	if tracker == nil {
+		return
+	}
+	latency, err := tracker.WaitFor(ctx, pod, d.effectiveDeletionTimeout())
+	if err != nil {
+		log.Printf("waiting for pod %s/%s to terminate: %v", pod.Namespace, pod.Name, err)
+		return
+	}
+	metrics.PodTerminationSeconds.Observe(latency.Seconds())
+}
+
+// forceDelete deletes pod directly, bypassing the eviction API, after
+// evictErr reports that it couldn't be evicted within the normal retry
+// budget (a stuck PDB, a broken admission webhook). It's always logged
+// loudly and notified, since it skips the PDB protections the eviction API
+// exists to enforce.
+func (d *customDrainer) forceDelete(ctx context.Context, pod *corev1.Pod, evictErr error) error {
+	log.Printf("WARNING: pod %s/%s could not be evicted (%v); force-deleting it with grace period %s", pod.Namespace, pod.Name, evictErr, d.forceDeleteGracePeriod)
+	if err := d.pods.Delete(ctx, pod, int64(d.forceDeleteGracePeriod.Seconds())); err != nil {
+		return fmt.Errorf("force-deleting pod %s/%s after eviction failure (%v): %w", pod.Namespace, pod.Name, evictErr, err)
+	}
+	if d.notifier != nil {
+		message := fmt.Sprintf("pod %s/%s could not be evicted (%v) and was force-deleted instead", pod.Namespace, pod.Name, evictErr)
+		if err := d.notifier.NotifyEviction(pod.Namespace, message); err != nil {
+			log.Printf("notifying about force-deleted pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+	}
+	return nil
+}
+
+// evictBatches evicts pods group by group, where a group is a run of
+// consecutive pods sharing the same EvictionOrderAnnotation value and
+// PVC-bearing status (see sortByEvictionOrder) -- so a later group never
+// starts until every pod in an earlier one has finished evicting, and in
+// particular no PVC-bearing pod starts evicting until every stateless pod
+// at the same EvictionOrderAnnotation value has. Within a group, up to
+// d.effectiveBatchSize() pods are evicted concurrently, so a dense node
+// isn't evicted fully serially but also never spawns an unbounded number
+// of eviction goroutines. It returns as soon as any eviction fails, along
+// with however many pods were successfully evicted first.
+func (d *customDrainer) evictBatches(ctx context.Context, pods []corev1.Pod, tracker *podDeletionTracker) (map[string]int, error) {
+	evictedByNamespace := map[string]int{}
+
+	for groupStart := 0; groupStart < len(pods); {
+		groupEnd := groupStart + 1
+		for groupEnd < len(pods) && evictionOrder(pods[groupEnd]) == evictionOrder(pods[groupStart]) && hasPVC(pods[groupEnd]) == hasPVC(pods[groupStart]) {
+			groupEnd++
+		}
+		group := pods[groupStart:groupEnd]
+
+		for start := 0; start < len(group); {
+			batchSize := d.effectiveBatchSize()
+			end := start + batchSize
+			if end > len(group) {
+				end = len(group)
+			}
+			batch := group[start:end]
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			errs := make([]error, len(batch))
+			for i := range batch {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					if err := d.rateLimiter.Wait(ctx); err != nil {
+						errs[i] = err
+						return
+					}
+					if err := d.pods.Evict(ctx, &batch[i]); err != nil {
+						if !d.forceDeleteAfterTimeout {
+							errs[i] = fmt.Errorf("evicting pod %s/%s: %w", batch[i].Namespace, batch[i].Name, err)
+							return
+						}
+						if delErr := d.forceDelete(ctx, &batch[i], err); delErr != nil {
+							errs[i] = delErr
+							return
+						}
+					}
+					mu.Lock()
+					evictedByNamespace[batch[i].Namespace]++
+					mu.Unlock()
+					d.awaitDeletion(ctx, tracker, &batch[i])
+				}(i)
+			}
+			wg.Wait()
+
+			for _, err := range errs {
+				if err != nil {
+					return evictedByNamespace, err
+				}
+			}
+			start = end
+		}
+
+		groupStart = groupEnd
+	}
+	return evictedByNamespace, nil
+}
+
+// effectiveBatchSize returns d.maxConcurrentEvictions, degraded down to 1
+// if d.maxMemoryMB is set and the process's current memory use has
+// crossed it -- graceful degradation to smaller (eventually serial)
+// batches instead of piling more concurrent evictions onto an
+// already-pressured process.
+func (d *customDrainer) effectiveBatchSize() int {
+	batchSize := d.maxConcurrentEvictions
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	if d.maxMemoryMB <= 0 {
+		return batchSize
+	}
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	usedMB := int(stats.Alloc / (1 << 20))
+
+	switch {
+	case usedMB >= d.maxMemoryMB:
+		return 1
+	case usedMB >= d.maxMemoryMB*3/4 && batchSize > 1:
+		return batchSize / 2
+	default:
+		return batchSize
+	}
+}
+
+// inDisruptionWindow reports whether pod is currently inside its own
+// DisruptionWindowAnnotation window. A pod without the annotation is
+// always considered in-window, since it declared no restriction of its
+// own.
+func inDisruptionWindow(pod corev1.Pod) (bool, error) {
+	cronExpr, ok := pod.Annotations[DisruptionWindowAnnotation]
+	if !ok {
+		return true, nil
+	}
+
+	duration := defaultDisruptionWindowDuration
+	if raw, ok := pod.Annotations[DisruptionWindowDurationAnnotation]; ok {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Printf("pod %s/%s has invalid %s annotation %q, using default of %s: %v", pod.Namespace, pod.Name, DisruptionWindowDurationAnnotation, raw, defaultDisruptionWindowDuration, err)
+		} else {
+			duration = parsed
+		}
+	}
+
+	window, err := schedule.NewWindow(cronExpr, duration)
+	if err != nil {
+		return false, err
+	}
+	return window.InWindow(time.Now()), nil
+}
+
+// checkTenantTier reports an error wrapping errPassoverNode if namespace is
+// annotated with NamespaceTierAnnotation and that tier isn't in
+// d.allowedTenantTiers. cache memoizes the lookup per namespace so a node
+// with many pods in the same namespace only fetches it once. A nil
+// d.namespaces (no NamespaceInterface wired up) disables the check
+// entirely, as if every tier were allowed.
+func (d *customDrainer) checkTenantTier(ctx context.Context, namespace string, cache map[string]error) error {
+	if d.namespaces == nil {
+		return nil
+	}
+	if err, ok := cache[namespace]; ok {
+		return err
+	}
+
+	ns, err := d.namespaces.GetNamespace(ctx, namespace)
+	if err != nil || ns == nil {
+		cache[namespace] = nil
+		if err != nil {
+			log.Printf("looking up namespace %s for tenant-tier check: %v", namespace, err)
+		}
+		return nil
+	}
+
+	tier, ok := ns.Annotations[NamespaceTierAnnotation]
+	if !ok || tierAllowed(tier, d.allowedTenantTiers) {
+		cache[namespace] = nil
+		return nil
+	}
+
+	tierErr := fmt.Errorf("namespace %s is tier %q, which is not in --allowed-tenant-tiers: %w", namespace, tier, errPassoverNode)
+	cache[namespace] = tierErr
+	return tierErr
+}
+
+// tierAllowed reports whether tier appears in allowed.
+func tierAllowed(tier string, allowed []string) bool {
+	for _, t := range allowed {
+		if t == tier {
+			return true
+		}
+	}
+	return false
+}
+
+// sortByEvictionOrder stable-sorts pods ascending by EvictionOrderAnnotation,
+// so dependency hints (e.g. evict app pods before the shared proxy they talk
+// to) are honored within a single node's drain, and then, within each
+// EvictionOrderAnnotation value, ascending by whether the pod mounts a PVC,
+// so stateless pods are evicted before PVC-bearing ones. That way, if a
+// drain times out partway through, it's the cheap-to-reschedule stateless
+// pods that are gone and the expensive-to-move stateful ones that are left
+// untouched.
+func sortByEvictionOrder(pods []corev1.Pod) {
+	sort.SliceStable(pods, func(i, j int) bool {
+		oi, oj := evictionOrder(pods[i]), evictionOrder(pods[j])
+		if oi != oj {
+			return oi < oj
+		}
+		return !hasPVC(pods[i]) && hasPVC(pods[j])
+	})
+}
+
+// hasPVC reports whether pod mounts a PersistentVolumeClaim-backed volume.
+func hasPVC(pod corev1.Pod) bool {
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func evictionOrder(pod corev1.Pod) int {
+	raw, ok := pod.Annotations[EvictionOrderAnnotation]
+	if !ok {
+		return 0
+	}
+	order, err := strconv.Atoi(raw)
+	if err != nil {
+		log.Printf("pod %s/%s has invalid %s annotation %q, ignoring: %v", pod.Namespace, pod.Name, EvictionOrderAnnotation, raw, err)
+		return 0
+	}
+	return order
+}
+
+func (d *customDrainer) effectivePolicy() BarePodPolicy {
+	if d.barePodPolicy == "" {
+		return BarePodPassoverNode
+	}
+	return d.barePodPolicy
+}
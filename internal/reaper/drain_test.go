@@ -0,0 +1,71 @@
+package reaper
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func withPVC(pod corev1.Pod) corev1.Pod {
+	pod.Spec.Volumes = []corev1.Volume{{
+		Name:         "data",
+		VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}},
+	}}
+	return pod
+}
+
+func withEvictionOrder(pod corev1.Pod, order string) corev1.Pod {
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[EvictionOrderAnnotation] = order
+	return pod
+}
+
+func named(name string) corev1.Pod {
+	return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func TestSortByEvictionOrder(t *testing.T) {
+	pods := []corev1.Pod{
+		withPVC(withEvictionOrder(named("stateful-second"), "2")),
+		withEvictionOrder(named("stateless-first"), "1"),
+		withPVC(named("stateful-default")),
+		named("stateless-default"),
+	}
+
+	sortByEvictionOrder(pods)
+
+	got := make([]string, len(pods))
+	for i, p := range pods {
+		got[i] = p.Name
+	}
+	want := []string{"stateless-default", "stateful-default", "stateless-first", "stateful-second"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sortByEvictionOrder order = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestHasPVC(t *testing.T) {
+	if hasPVC(named("no-volumes")) {
+		t.Fatal("hasPVC(no-volumes) = true, want false")
+	}
+	if !hasPVC(withPVC(named("has-pvc"))) {
+		t.Fatal("hasPVC(has-pvc) = false, want true")
+	}
+}
+
+func TestEvictionOrder(t *testing.T) {
+	if order := evictionOrder(named("no-annotation")); order != 0 {
+		t.Fatalf("evictionOrder(no-annotation) = %d, want 0", order)
+	}
+	if order := evictionOrder(withEvictionOrder(named("ordered"), "5")); order != 5 {
+		t.Fatalf("evictionOrder(ordered) = %d, want 5", order)
+	}
+	if order := evictionOrder(withEvictionOrder(named("malformed"), "not-a-number")); order != 0 {
+		t.Fatalf("evictionOrder(malformed) = %d, want 0 (ignored, logged)", order)
+	}
+}
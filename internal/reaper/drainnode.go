@@ -0,0 +1,162 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/BrianKopp/grim-reaper/internal/notify"
+)
+
+// DrainOptions configures a standalone DrainNode call. Its fields mirror
+// the subset of config.Config and GrimReaper that customDrainer actually
+// consumes during a normal Run, so a caller outside grim-reaper's own
+// selection/budget pipeline -- a cluster-upgrade controller, for
+// instance -- gets the same hardened drain behavior (PDB handling,
+// bare-pod policy, tenant-tier gating, batching) without pulling in
+// node selection, MinNodes/MaxNodesDelete/ReapFraction, or node/instance
+// deletion.
+type DrainOptions struct {
+	Nodes      NodeInterface
+	Pods       PodEvictor
+	Namespaces NamespaceInterface
+
+	BarePodPolicy          BarePodPolicy
+	HonorSafeToEvict       bool
+	AllowedTenantTiers     []string
+	MaxConcurrentEvictions int
+	EvictionRateLimit      float64
+	MaxMemoryMB            int
+	EvictDeletionTimeout   time.Duration
+
+	// ForceDeleteAfterTimeout and ForceDeleteGracePeriod, exactly like
+	// config.Config's fields of the same name, fall back to deleting a pod
+	// directly once its eviction retries are exhausted instead of failing
+	// the whole drain.
+	ForceDeleteAfterTimeout bool
+	ForceDeleteGracePeriod  time.Duration
+
+	// SkipCordon, if true, assumes the caller has already cordoned the
+	// node and leaves it alone. The zero value cordons the node before
+	// draining it, which is almost always what's wanted.
+	SkipCordon bool
+
+	// Recorder, if set, emits Events against the Node object as it's
+	// cordoned and drained, exactly like GrimReaper.Recorder.
+	Recorder record.EventRecorder
+
+	// Notifier, if set, notifies each affected namespace's channel of its
+	// evicted pod count, exactly like GrimReaper.Notifier.
+	Notifier *notify.Router
+}
+
+// DrainReport is the outcome of a single DrainNode call.
+type DrainReport struct {
+	Node               string         `json:"node"`
+	DurationSeconds    float64        `json:"durationSeconds"`
+	EvictedPods        int            `json:"evictedPods"`
+	EvictedByNamespace map[string]int `json:"evictedByNamespace,omitempty"`
+}
+
+// DrainNode cordons (unless opts.SkipCordon is set) and drains the node
+// named name, using the same customDrainer logic GrimReaper.Run uses
+// internally, then returns without deleting the node object or terminating
+// any cloud instance -- that's left to the caller. It's the entry point
+// for other controllers in the platform that want grim-reaper's hardened
+// drain without its node-selection or budget logic.
+func DrainNode(ctx context.Context, name string, opts DrainOptions) (*DrainReport, error) {
+	if opts.Nodes == nil || opts.Pods == nil {
+		return nil, fmt.Errorf("draining node %s: DrainOptions.Nodes and DrainOptions.Pods are required", name)
+	}
+
+	node, err := findNodeByName(ctx, opts.Nodes, name)
+	if err != nil {
+		return nil, fmt.Errorf("finding node %s: %w", name, err)
+	}
+
+	if !opts.SkipCordon {
+		if err := opts.Nodes.Cordon(ctx, node); err != nil {
+			return nil, fmt.Errorf("cordoning node %s: %w", name, err)
+		}
+	}
+
+	drainer := &customDrainer{
+		pods:                    opts.Pods,
+		barePodPolicy:           opts.BarePodPolicy,
+		honorSafeToEvict:        opts.HonorSafeToEvict,
+		namespaces:              opts.Namespaces,
+		allowedTenantTiers:      opts.AllowedTenantTiers,
+		maxConcurrentEvictions:  opts.MaxConcurrentEvictions,
+		maxMemoryMB:             opts.MaxMemoryMB,
+		deletionTimeout:         opts.EvictDeletionTimeout,
+		forceDeleteAfterTimeout: opts.ForceDeleteAfterTimeout,
+		forceDeleteGracePeriod:  opts.ForceDeleteGracePeriod,
+		notifier:                opts.Notifier,
+		rateLimiter:             newEvictionRateLimiter(opts.EvictionRateLimit),
+	}
+
+	start := time.Now()
+	recordEvent(opts.Recorder, node, corev1.EventTypeNormal, "DrainStarted", "grim-reaper started draining this node")
+	evictedByNamespace, err := drainer.Drain(ctx, node)
+	if err != nil {
+		recordEvent(opts.Recorder, node, corev1.EventTypeWarning, "PodEvictionFailed", "draining this node failed: %v", err)
+		return nil, fmt.Errorf("draining node %s: %w", name, err)
+	}
+	recordEvent(opts.Recorder, node, corev1.EventTypeNormal, "DrainCompleted", "grim-reaper finished draining this node, evicted %v", evictedByNamespace)
+	notifyDrainEvictions(opts.Notifier, node, evictedByNamespace)
+
+	evictedPods := 0
+	for _, count := range evictedByNamespace {
+		evictedPods += count
+	}
+
+	return &DrainReport{
+		Node:               name,
+		DurationSeconds:    time.Since(start).Seconds(),
+		EvictedPods:        evictedPods,
+		EvictedByNamespace: evictedByNamespace,
+	}, nil
+}
+
+// findNodeByName returns the node named name, or an error if it doesn't
+// exist. NodeInterface only supports listing by label selector, so this
+// lists every node and filters client-side.
+func findNodeByName(ctx context.Context, nodes NodeInterface, name string) (*corev1.Node, error) {
+	list, err := nodes.ListNodes(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if list[i].Name == name {
+			return &list[i], nil
+		}
+	}
+	return nil, fmt.Errorf("node %s not found", name)
+}
+
+// recordEvent is DrainNode's standalone equivalent of GrimReaper.recordEvent.
+func recordEvent(recorder record.EventRecorder, node *corev1.Node, eventType, reason, messageFmt string, args ...interface{}) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(node, eventType, reason, messageFmt, args...)
+}
+
+// notifyDrainEvictions is DrainNode's standalone equivalent of
+// GrimReaper.notifyEvictions, minus the reap-reason annotation a
+// selection-driven harvest would have set.
+func notifyDrainEvictions(notifier *notify.Router, node *corev1.Node, evictedByNamespace map[string]int) {
+	if notifier == nil {
+		return
+	}
+	for ns, count := range evictedByNamespace {
+		message := fmt.Sprintf("grim-reaper evicted %d pod(s) in namespace %s from node %s", count, ns, node.Name)
+		if err := notifier.NotifyEviction(ns, message); err != nil {
+			log.Printf("notifying eviction for namespace %s: %v", ns, err)
+		}
+	}
+}
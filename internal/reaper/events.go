@@ -0,0 +1,97 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultEventNamespace is where Events involving a cluster-scoped object
+// like a Node are conventionally recorded, since an Event itself must live
+// in some namespace.
+const defaultEventNamespace = "default"
+
+// eventClient is the default EventReader implementation, backed by a real
+// (or fake) client-go clientset.
+type eventClient struct {
+	clientset kubernetes.Interface
+}
+
+// NewEventClient returns an EventReader backed by clientset.
+func NewEventClient(clientset kubernetes.Interface) EventReader {
+	return &eventClient{clientset: clientset}
+}
+
+func (e *eventClient) ListEventsForObject(ctx context.Context, namespace, name, kind string) ([]corev1.Event, error) {
+	selector := fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=%s", name, kind)
+	list, err := e.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// correlateEvents collects the Events involving node and pods with a last
+// occurrence at or after since, and summarizes them grouped by Reason, for
+// NodeReapReport.CorrelatedEvents. The same underlying condition (e.g.
+// repeated FailedScheduling retries) usually fires many times with
+// slightly different messages, and a responder doing post-reap impact
+// analysis cares about what happened, not how many times it was reported,
+// so only one example message per Reason is kept. Returns nil if g.Events
+// is unset.
+func (g *GrimReaper) correlateEvents(ctx context.Context, node *corev1.Node, pods []corev1.Pod, since time.Time) []CorrelatedEvent {
+	if g.Events == nil {
+		return nil
+	}
+
+	byReason := map[string]*CorrelatedEvent{}
+	collect := func(namespace, name, kind string) {
+		if namespace == "" {
+			namespace = defaultEventNamespace
+		}
+		events, err := g.Events.ListEventsForObject(ctx, namespace, name, kind)
+		if err != nil {
+			log.Printf("collecting events for %s %s/%s: %v", kind, namespace, name, err)
+			return
+		}
+		for i := range events {
+			last := events[i].LastTimestamp.Time
+			if last.IsZero() {
+				last = events[i].FirstTimestamp.Time
+			}
+			if last.Before(since) {
+				continue
+			}
+
+			ce, ok := byReason[events[i].Reason]
+			if !ok {
+				ce = &CorrelatedEvent{Reason: events[i].Reason}
+				byReason[events[i].Reason] = ce
+			}
+			count := events[i].Count
+			if count < 1 {
+				count = 1
+			}
+			ce.Count += count
+			ce.LastMessage = events[i].Message
+		}
+	}
+
+	collect(node.Namespace, node.Name, "Node")
+	for i := range pods {
+		collect(pods[i].Namespace, pods[i].Name, "Pod")
+	}
+
+	summary := make([]CorrelatedEvent, 0, len(byReason))
+	for _, ce := range byReason {
+		summary = append(summary, *ce)
+	}
+	sort.Slice(summary, func(i, j int) bool { return summary[i].Reason < summary[j].Reason })
+	return summary
+}
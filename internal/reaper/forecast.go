@@ -0,0 +1,86 @@
+package reaper
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WorkloadDisruption aggregates, for one workload, how many of its pods
+// would be disrupted by reaping a set of nodes and how many of those are
+// currently blocked by a PodDisruptionBudget, so an application owner
+// reviewing a plan can assess impact on their service at a glance.
+type WorkloadDisruption struct {
+	Namespace      string
+	Kind           string
+	Name           string
+	PodsDisrupted  int
+	PodsPDBBlocked int
+}
+
+type workloadKey struct {
+	namespace string
+	kind      string
+	name      string
+}
+
+// ForecastDisruption lists the pods on nodes and aggregates them per
+// controlling workload, dry-run evicting each pod to determine whether a
+// PodDisruptionBudget would currently block it.
+func ForecastDisruption(ctx context.Context, nodes []corev1.Node, pods PodEvictor) ([]WorkloadDisruption, error) {
+	byWorkload := map[workloadKey]*WorkloadDisruption{}
+
+	for i := range nodes {
+		podList, err := pods.ListPodsOnNode(ctx, nodes[i].Name)
+		if err != nil {
+			return nil, err
+		}
+
+		for j := range podList {
+			key, kind, name := owningWorkload(podList[j])
+			wd, ok := byWorkload[key]
+			if !ok {
+				wd = &WorkloadDisruption{Namespace: podList[j].Namespace, Kind: kind, Name: name}
+				byWorkload[key] = wd
+			}
+			wd.PodsDisrupted++
+			if err := pods.EvictDryRun(ctx, &podList[j]); err != nil {
+				wd.PodsPDBBlocked++
+			}
+		}
+	}
+
+	forecast := make([]WorkloadDisruption, 0, len(byWorkload))
+	for _, wd := range byWorkload {
+		forecast = append(forecast, *wd)
+	}
+	sort.Slice(forecast, func(i, j int) bool {
+		if forecast[i].Namespace != forecast[j].Namespace {
+			return forecast[i].Namespace < forecast[j].Namespace
+		}
+		return forecast[i].Name < forecast[j].Name
+	})
+	return forecast, nil
+}
+
+// owningWorkload returns the controlling owner's kind and name for pod, or
+// "Pod"/pod.Name if it has no controller (a bare pod).
+func owningWorkload(pod corev1.Pod) (workloadKey, string, string) {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller != nil && *owner.Controller {
+			return workloadKey{pod.Namespace, owner.Kind, owner.Name}, owner.Kind, owner.Name
+		}
+	}
+	return workloadKey{pod.Namespace, "Pod", pod.Name}, "Pod", pod.Name
+}
+
+// LogForecast writes forecast to the log in a format suitable for a human
+// reviewing a dry-run plan.
+func LogForecast(forecast []WorkloadDisruption) {
+	for _, wd := range forecast {
+		log.Printf("plan: %s/%s %q: %d pod(s) disrupted, %d currently blocked by a PodDisruptionBudget",
+			wd.Namespace, wd.Kind, wd.Name, wd.PodsDisrupted, wd.PodsPDBBlocked)
+	}
+}
@@ -0,0 +1,27 @@
+package reaper
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// fragmentationScore estimates how much of a node's capacity is stranded:
+// allocated but unusable because one resource dimension is exhausted while
+// another sits idle. Higher scores mean the node is a better candidate for
+// reaping, since repacking its pods elsewhere tends to improve overall
+// bin-packing.
+//
+// The score is deliberately simple: it compares the fraction of requested
+// CPU against the fraction of requested memory and returns the gap between
+// them. A node running hot on memory but cold on CPU (or vice versa) scores
+// high; a node that's evenly utilized (or empty, or full) scores low.
+func fragmentationScore(ctx context.Context, node corev1.Node, pods PodEvictor) float64 {
+	cpuFraction, memFraction := nodeRequestedFractions(ctx, node, pods)
+
+	gap := cpuFraction - memFraction
+	if gap < 0 {
+		gap = -gap
+	}
+	return gap
+}
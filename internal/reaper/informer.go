@@ -0,0 +1,108 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// nodeNameIndex indexes the pod informer by the node a pod is scheduled
+// onto, so ListPodsOnNode can look them up in the informer's local cache
+// instead of issuing a fresh, server-side-filtered List call every time
+// it's asked about a node. GetNodesToReap's selection pipeline calls it
+// once per candidate node across several independent filter stages
+// (density, capacity headroom, scheduling feasibility, ...), which turns
+// into thousands of redundant API calls on a cluster with thousands of
+// nodes without this.
+const nodeNameIndex = "nodeName"
+
+func podNodeNameIndexFunc(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("expected *corev1.Pod, got %T", obj)
+	}
+	if pod.Spec.NodeName == "" {
+		return nil, nil
+	}
+	return []string{pod.Spec.NodeName}, nil
+}
+
+// informerPodEvictor wraps a *podEvictor, serving ListPodsOnNode,
+// ListPendingPods, and ListPods from a SharedIndexInformer's local cache
+// instead of the API server. Every other PodEvictor method -- Evict,
+// EvictDryRun, Delete, WatchPodsOnNode -- is promoted straight through to
+// the embedded podEvictor unchanged, since eviction and watching a single
+// node's drain aren't something a periodically-resynced cache can stand in
+// for.
+type informerPodEvictor struct {
+	*podEvictor
+	informer cache.SharedIndexInformer
+}
+
+// NewInformerPodEvictor returns a PodEvictor backed by delegate for writes
+// and a cluster-wide pod informer for reads, started and synced against
+// ctx before returning. The informer keeps running, resyncing in the
+// background, until ctx is cancelled. Returns an error if delegate isn't a
+// *podEvictor (the type NewPodEvictor and its variants return), or if the
+// informer's initial cache sync doesn't complete before syncTimeout.
+func NewInformerPodEvictor(ctx context.Context, clientset kubernetes.Interface, delegate PodEvictor, syncTimeout time.Duration) (PodEvictor, error) {
+	base, ok := delegate.(*podEvictor)
+	if !ok {
+		return nil, fmt.Errorf("informer pod evictor requires a *podEvictor delegate, got %T", delegate)
+	}
+
+	factory := informers.NewSharedInformerFactory(clientset, 10*time.Minute)
+	informer := factory.Core().V1().Pods().Informer()
+	if err := informer.AddIndexers(cache.Indexers{nodeNameIndex: podNodeNameIndexFunc}); err != nil {
+		return nil, fmt.Errorf("indexing pod informer by node name: %w", err)
+	}
+
+	factory.Start(ctx.Done())
+
+	syncCtx, cancel := context.WithTimeout(ctx, syncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(syncCtx.Done(), informer.HasSynced) {
+		return nil, fmt.Errorf("pod informer cache did not sync within %s", syncTimeout)
+	}
+
+	return &informerPodEvictor{podEvictor: base, informer: informer}, nil
+}
+
+func (p *informerPodEvictor) ListPodsOnNode(ctx context.Context, nodeName string) ([]corev1.Pod, error) {
+	objs, err := p.informer.GetIndexer().ByIndex(nodeNameIndex, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	return podsFromCache(objs), nil
+}
+
+func (p *informerPodEvictor) ListPendingPods(ctx context.Context) ([]corev1.Pod, error) {
+	var pending []corev1.Pod
+	for _, obj := range p.informer.GetStore().List() {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		pending = append(pending, *pod)
+	}
+	return pending, nil
+}
+
+func (p *informerPodEvictor) ListPods(ctx context.Context) ([]corev1.Pod, error) {
+	return podsFromCache(p.informer.GetStore().List()), nil
+}
+
+func podsFromCache(objs []interface{}) []corev1.Pod {
+	pods := make([]corev1.Pod, 0, len(objs))
+	for _, obj := range objs {
+		if pod, ok := obj.(*corev1.Pod); ok {
+			pods = append(pods, *pod)
+		}
+	}
+	return pods
+}
@@ -0,0 +1,63 @@
+package reaper
+
+import (
+	"context"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apierrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/drain"
+)
+
+// kubectlDrainer drains nodes using k8s.io/kubectl/pkg/drain, the same
+// library backing `kubectl drain`, for users who want identical filters
+// and error aggregation to the CLI tool rather than grim-reaper's own
+// evictor.
+type kubectlDrainer struct {
+	helper               *drain.Helper
+	evictionGroupVersion schema.GroupVersion
+}
+
+// NewKubectlDrainer returns a Drainer backed by the kubectl drain helper,
+// evicting against the given eviction API version (see
+// DetectEvictionAPIVersion).
+func NewKubectlDrainer(clientset kubernetes.Interface, timeout time.Duration, evictionAPIVersion string) Drainer {
+	groupVersion := policyv1beta1.SchemeGroupVersion
+	if evictionAPIVersion == "v1" {
+		groupVersion = policyv1.SchemeGroupVersion
+	}
+	return &kubectlDrainer{
+		helper: &drain.Helper{
+			Client:              clientset,
+			Force:               true,
+			IgnoreAllDaemonSets: true,
+			DeleteEmptyDirData:  true,
+			Timeout:             timeout,
+			Out:                 io.Discard,
+			ErrOut:              io.Discard,
+		},
+		evictionGroupVersion: groupVersion,
+	}
+}
+
+func (k *kubectlDrainer) Drain(ctx context.Context, node *corev1.Node) (map[string]int, error) {
+	k.helper.Ctx = ctx
+	podList, errs := k.helper.GetPodsForDeletion(node.Name)
+	if len(errs) > 0 {
+		return nil, apierrors.NewAggregate(errs)
+	}
+
+	evictedByNamespace := map[string]int{}
+	for _, pod := range podList.Pods() {
+		if err := k.helper.EvictPod(pod, k.evictionGroupVersion); err != nil {
+			return evictedByNamespace, err
+		}
+		evictedByNamespace[pod.Namespace]++
+	}
+	return evictedByNamespace, nil
+}
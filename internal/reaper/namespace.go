@@ -0,0 +1,24 @@
+package reaper
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// namespaceClient is the default NamespaceInterface implementation, backed
+// by a real (or fake) client-go clientset.
+type namespaceClient struct {
+	clientset kubernetes.Interface
+}
+
+// NewNamespaceClient returns a NamespaceInterface backed by clientset.
+func NewNamespaceClient(clientset kubernetes.Interface) NamespaceInterface {
+	return &namespaceClient{clientset: clientset}
+}
+
+func (n *namespaceClient) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	return n.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+}
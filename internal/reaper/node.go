@@ -0,0 +1,146 @@
+package reaper
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// nodeClient is the default NodeInterface implementation, backed by a real
+// (or fake) client-go clientset.
+type nodeClient struct {
+	clientset kubernetes.Interface
+}
+
+// NewNodeClient returns a NodeInterface backed by clientset.
+func NewNodeClient(clientset kubernetes.Interface) NodeInterface {
+	return &nodeClient{clientset: clientset}
+}
+
+func (n *nodeClient) ListNodes(ctx context.Context, selector string) ([]corev1.Node, error) {
+	list, err := n.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func (n *nodeClient) Cordon(ctx context.Context, node *corev1.Node) error {
+	return n.patchUnschedulable(ctx, node, true)
+}
+
+func (n *nodeClient) Uncordon(ctx context.Context, node *corev1.Node) error {
+	return n.patchUnschedulable(ctx, node, false)
+}
+
+func (n *nodeClient) patchUnschedulable(ctx context.Context, node *corev1.Node, unschedulable bool) error {
+	patch := []byte(`{"spec":{"unschedulable":` + boolString(unschedulable) + `}}`)
+	_, err := n.clientset.CoreV1().Nodes().Patch(ctx, node.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func (n *nodeClient) Delete(ctx context.Context, node *corev1.Node) error {
+	return n.clientset.CoreV1().Nodes().Delete(ctx, node.Name, metav1.DeleteOptions{})
+}
+
+func (n *nodeClient) Annotate(ctx context.Context, node *corev1.Node, annotations map[string]interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	updated, err := n.clientset.CoreV1().Nodes().Patch(ctx, node.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return err
+	}
+	node.Annotations = updated.Annotations
+	return nil
+}
+
+func (n *nodeClient) Taint(ctx context.Context, node *corev1.Node, key, value string, effect corev1.TaintEffect) error {
+	now := metav1.NewTime(time.Now())
+	taints := make([]corev1.Taint, 0, len(node.Spec.Taints)+1)
+	for _, t := range node.Spec.Taints {
+		if t.Key != key {
+			taints = append(taints, t)
+		}
+	}
+	taints = append(taints, corev1.Taint{Key: key, Value: value, Effect: effect, TimeAdded: &now})
+	return n.patchTaints(ctx, node, taints)
+}
+
+func (n *nodeClient) Untaint(ctx context.Context, node *corev1.Node, key string) error {
+	taints := make([]corev1.Taint, 0, len(node.Spec.Taints))
+	for _, t := range node.Spec.Taints {
+		if t.Key != key {
+			taints = append(taints, t)
+		}
+	}
+	return n.patchTaints(ctx, node, taints)
+}
+
+func (n *nodeClient) SetCondition(ctx context.Context, node *corev1.Node, condition corev1.NodeCondition) error {
+	conditions := make([]corev1.NodeCondition, 0, len(node.Status.Conditions)+1)
+	for _, c := range node.Status.Conditions {
+		if c.Type != condition.Type {
+			conditions = append(conditions, c)
+		}
+	}
+	conditions = append(conditions, condition)
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": conditions,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	updated, err := n.clientset.CoreV1().Nodes().Patch(ctx, node.Name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	if err != nil {
+		return err
+	}
+	node.Status.Conditions = updated.Status.Conditions
+	return nil
+}
+
+// patchTaints replaces node's entire taint list with taints. A JSON merge
+// patch can't add or remove a single element of a list field, so callers
+// build the full desired list (copying over whatever they aren't changing)
+// before calling this.
+func (n *nodeClient) patchTaints(ctx context.Context, node *corev1.Node, taints []corev1.Taint) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"taints": taints,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	updated, err := n.clientset.CoreV1().Nodes().Patch(ctx, node.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return err
+	}
+	node.Spec.Taints = updated.Spec.Taints
+	return nil
+}
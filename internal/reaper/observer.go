@@ -0,0 +1,48 @@
+package reaper
+
+import (
+	"context"
+	"log"
+
+	"github.com/BrianKopp/grim-reaper/internal/metrics"
+)
+
+// ObserveRun runs the full selection pipeline and validates, via dry-run
+// evictions, whether each candidate node's pods could actually be drained
+// today -- without cordoning, draining, or deleting anything. It's meant
+// to run continuously to build confidence before enabling destructive
+// mode.
+func (g *GrimReaper) ObserveRun(ctx context.Context) error {
+	nodes, err := g.GetNodesToReap(ctx)
+	if err != nil {
+		return err
+	}
+
+	forecast, err := ForecastDisruption(ctx, nodes, g.Pods)
+	if err != nil {
+		log.Printf("observer: forecasting per-workload disruption: %v", err)
+	} else {
+		LogForecast(forecast)
+	}
+
+	for i := range nodes {
+		pods, err := g.Pods.ListPodsOnNode(ctx, nodes[i].Name)
+		if err != nil {
+			log.Printf("observer: listing pods on node %s: %v", nodes[i].Name, err)
+			continue
+		}
+
+		blocked := 0
+		for j := range pods {
+			if err := g.Pods.EvictDryRun(ctx, &pods[j]); err != nil {
+				blocked++
+			}
+		}
+
+		metrics.ObserverSimulatedDrainBlockedPods.WithLabelValues(nodes[i].Name).Set(float64(blocked))
+		log.Printf("observer: node %s would reap %d pod(s), %d currently blocked", nodes[i].Name, len(pods), blocked)
+	}
+
+	metrics.ObserverSimulatedReapableNodes.Set(float64(len(nodes)))
+	return nil
+}
@@ -0,0 +1,67 @@
+package reaper
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// pacingDelay computes how long to wait before harvesting the next node,
+// scaling with cluster size and how long pods have recently sat Pending, so
+// the same configuration behaves sensibly on both a 20-node and a
+// 2000-node cluster without per-cluster tuning.
+//
+// The delay grows logarithmically with clusterSize (draining one node out
+// of 2000 can be more patient than one node out of 20) and is stretched
+// further if the scheduler is visibly struggling to place pods.
+func pacingDelay(base time.Duration, clusterSize int, pendingPodAges []time.Duration) time.Duration {
+	if clusterSize < 1 {
+		clusterSize = 1
+	}
+	scale := math.Log2(float64(clusterSize) + 1)
+	delay := time.Duration(float64(base) * scale)
+
+	if p95 := percentile(pendingPodAges, 0.95); p95 > delay {
+		delay = p95
+	}
+	return delay
+}
+
+// percentile returns the pth percentile (0..1) of durations, or 0 if empty.
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	idx := int(p * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+// pendingPodAges returns how long each Pending pod in pods has been
+// waiting to be scheduled.
+func pendingPodAges(pods []corev1.Pod, now time.Time) []time.Duration {
+	var ages []time.Duration
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		ages = append(ages, now.Sub(pod.CreationTimestamp.Time))
+	}
+	return ages
+}
+
+// oldestPendingAge returns the longest of pendingPodAges, or 0 if pods has
+// no Pending pods.
+func oldestPendingAge(pods []corev1.Pod, now time.Time) time.Duration {
+	var oldest time.Duration
+	for _, age := range pendingPodAges(pods, now) {
+		if age > oldest {
+			oldest = age
+		}
+	}
+	return oldest
+}
@@ -0,0 +1,28 @@
+package reaper
+
+import (
+	"context"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// pdbClient is the default PDBInterface implementation, backed by a real
+// (or fake) client-go clientset.
+type pdbClient struct {
+	clientset kubernetes.Interface
+}
+
+// NewPDBClient returns a PDBInterface backed by clientset.
+func NewPDBClient(clientset kubernetes.Interface) PDBInterface {
+	return &pdbClient{clientset: clientset}
+}
+
+func (c *pdbClient) ListPDBs(ctx context.Context, namespace string) ([]policyv1.PodDisruptionBudget, error) {
+	list, err := c.clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
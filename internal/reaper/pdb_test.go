@@ -0,0 +1,52 @@
+package reaper
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPdbCoversPod(t *testing.T) {
+	pdb := &policyv1.PodDisruptionBudget{
+		Spec: policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "guarded"}}},
+	}
+	matching := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "guarded"}}}
+	other := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "unrelated"}}}
+
+	if !pdbCoversPod(pdb, matching) {
+		t.Fatal("pdbCoversPod: expected matching labels to be covered")
+	}
+	if pdbCoversPod(pdb, other) {
+		t.Fatal("pdbCoversPod: expected non-matching labels to not be covered")
+	}
+
+	emptySelector := &policyv1.PodDisruptionBudget{Spec: policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{}}}
+	if pdbCoversPod(emptySelector, matching) {
+		t.Fatal("pdbCoversPod: an empty selector should not be treated as covering every pod")
+	}
+}
+
+func TestPdbStructurallyInfeasible(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected int32
+		desired  int32
+		want     bool
+	}{
+		{name: "single replica requiring full health", expected: 1, desired: 1, want: true},
+		{name: "three replicas, one allowed unhealthy", expected: 3, desired: 2, want: false},
+		{name: "no pods covered", expected: 0, desired: 0, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdb := &policyv1.PodDisruptionBudget{
+				Status: policyv1.PodDisruptionBudgetStatus{ExpectedPods: tt.expected, DesiredHealthy: tt.desired},
+			}
+			if got := pdbStructurallyInfeasible(pdb); got != tt.want {
+				t.Fatalf("pdbStructurallyInfeasible(expected=%d, desired=%d) = %v, want %v", tt.expected, tt.desired, got, tt.want)
+			}
+		})
+	}
+}
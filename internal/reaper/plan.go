@@ -0,0 +1,74 @@
+package reaper
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// PlannedNode describes one node Plan would cordon, drain, and delete.
+type PlannedNode struct {
+	Name        string `json:"name"`
+	Reason      string `json:"reason"`
+	Pods        int    `json:"pods"`
+	BlockedPods int    `json:"blockedPods"`
+
+	// StatefulBytes is the total provisioned storage of the node's
+	// PVC-backed pods, and EstimatedReplicationDelaySeconds the extra
+	// pacing delay that data would add after this node is harvested (see
+	// GrimReaper.statefulReplicationDelay). Both are zero unless PVCs and
+	// StatefulReplicationRateMBPerSec are configured.
+	StatefulBytes                    int64   `json:"statefulBytes,omitempty"`
+	EstimatedReplicationDelaySeconds float64 `json:"estimatedReplicationDelaySeconds,omitempty"`
+}
+
+// Plan is the terraform-style preview Plan returns: which nodes would be
+// reaped and why, and which were passed over and why, computed without
+// mutating the cluster.
+type Plan struct {
+	GeneratedAt time.Time            `json:"generatedAt"`
+	ToReap      []PlannedNode        `json:"toReap"`
+	PassedOver  []NodePassoverReport `json:"passedOver"`
+}
+
+// Plan runs selection and every safety check -- exactly what Run would act
+// on -- without cordoning, draining, or deleting anything, and reports
+// which nodes would be reaped and why, alongside which were passed over
+// and why.
+func (g *GrimReaper) Plan(ctx context.Context) (*Plan, error) {
+	plan := &Plan{GeneratedAt: time.Now()}
+	g.PassoverObserver = func(nodeName, reason string) {
+		plan.PassedOver = append(plan.PassedOver, NodePassoverReport{Name: nodeName, Reason: reason})
+	}
+	defer func() { g.PassoverObserver = nil }()
+
+	nodes, err := g.GetNodesToReap(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range nodes {
+		pods, err := g.Pods.ListPodsOnNode(ctx, nodes[i].Name)
+		if err != nil {
+			log.Printf("plan: listing pods on node %s: %v", nodes[i].Name, err)
+		}
+
+		blocked := 0
+		for j := range pods {
+			if err := g.Pods.EvictDryRun(ctx, &pods[j]); err != nil {
+				blocked++
+			}
+		}
+
+		statefulBytes := g.nodeStatefulBytes(ctx, pods)
+		plan.ToReap = append(plan.ToReap, PlannedNode{
+			Name:                             nodes[i].Name,
+			Reason:                           reapReason(&nodes[i]),
+			Pods:                             len(pods),
+			BlockedPods:                      blocked,
+			StatefulBytes:                    statefulBytes,
+			EstimatedReplicationDelaySeconds: g.statefulReplicationDelay(statefulBytes).Seconds(),
+		})
+	}
+	return plan, nil
+}
@@ -0,0 +1,247 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/BrianKopp/grim-reaper/internal/metrics"
+)
+
+// podEvictor is the default PodEvictor, backed by a real (or fake)
+// client-go clientset.
+type podEvictor struct {
+	clientset kubernetes.Interface
+
+	// Backoff governs how long to wait between retries when an eviction is
+	// rejected (typically by a PodDisruptionBudget). MaxRetries bounds how
+	// many times a single eviction is retried before giving up.
+	Backoff    BackoffPolicy
+	MaxRetries int
+
+	// ExtendedGracePeriod, when true, lets Evict honor a pod's own
+	// terminationGracePeriodSeconds even past GracefulTerminationSeconds,
+	// up to MaxGracePeriodSeconds, instead of always capping to the
+	// smaller of the two.
+	ExtendedGracePeriod   bool
+	MaxGracePeriodSeconds int64
+
+	// EvictionAPIVersion selects which Eviction subresource version to call:
+	// "v1" (policy/v1, the only option left on Kubernetes 1.25+) or
+	// "v1beta1" (the default, for older servers that predate policy/v1's
+	// eviction subresource). Set via DetectEvictionAPIVersion so grim-reaper
+	// doesn't have to guess.
+	EvictionAPIVersion string
+}
+
+// NewPodEvictor returns a PodEvictor backed by clientset, retrying rejected
+// evictions with a jittered exponential backoff by default, against the
+// policy/v1beta1 eviction subresource.
+func NewPodEvictor(clientset kubernetes.Interface) PodEvictor {
+	return &podEvictor{
+		clientset:  clientset,
+		Backoff:    JitteredExponentialBackoff{Base: 2 * time.Second, Max: time.Minute},
+		MaxRetries: 12,
+	}
+}
+
+// NewPodEvictorWithBackoff returns a PodEvictor backed by clientset that
+// retries rejected evictions according to backoff, up to maxRetries times,
+// against the given eviction API version (see EvictionAPIVersion).
+func NewPodEvictorWithBackoff(clientset kubernetes.Interface, backoff BackoffPolicy, maxRetries int, evictionAPIVersion string) PodEvictor {
+	return &podEvictor{clientset: clientset, Backoff: backoff, MaxRetries: maxRetries, EvictionAPIVersion: evictionAPIVersion}
+}
+
+// NewPodEvictorWithExtendedGracePeriod is like NewPodEvictorWithBackoff, but
+// honors a pod's own terminationGracePeriodSeconds past the default
+// GracefulTerminationSeconds ceiling, up to maxGracePeriodSeconds, so
+// slow-shutdown workloads like databases aren't cut short.
+func NewPodEvictorWithExtendedGracePeriod(clientset kubernetes.Interface, backoff BackoffPolicy, maxRetries int, maxGracePeriodSeconds int64, evictionAPIVersion string) PodEvictor {
+	return &podEvictor{
+		clientset:             clientset,
+		Backoff:               backoff,
+		MaxRetries:            maxRetries,
+		ExtendedGracePeriod:   true,
+		MaxGracePeriodSeconds: maxGracePeriodSeconds,
+		EvictionAPIVersion:    evictionAPIVersion,
+	}
+}
+
+func (p *podEvictor) ListPodsOnNode(ctx context.Context, nodeName string) ([]corev1.Pod, error) {
+	list, err := p.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// EvictDryRun validates whether pod could be evicted (e.g. whether its
+// PDBs would allow it) without actually evicting it.
+func (p *podEvictor) EvictDryRun(ctx context.Context, pod *corev1.Pod) error {
+	objectMeta := metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}
+	deleteOptions := &metav1.DeleteOptions{DryRun: []string{metav1.DryRunAll}}
+
+	if p.EvictionAPIVersion == "v1" {
+		eviction := &policyv1.Eviction{ObjectMeta: objectMeta, DeleteOptions: deleteOptions}
+		return p.clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+	}
+	eviction := &policyv1beta1.Eviction{ObjectMeta: objectMeta, DeleteOptions: deleteOptions}
+	return p.clientset.PolicyV1beta1().Evictions(pod.Namespace).Evict(ctx, eviction)
+}
+
+func (p *podEvictor) ListPendingPods(ctx context.Context) ([]corev1.Pod, error) {
+	list, err := p.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase=Pending",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func (p *podEvictor) ListPods(ctx context.Context) ([]corev1.Pod, error) {
+	list, err := p.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// Evict evicts pod, capping the grace period to whichever is smaller: the
+// pod's own terminationGracePeriodSeconds, or a ceiling. The ceiling is
+// GracefulTerminationSeconds normally, or MaxGracePeriodSeconds when
+// ExtendedGracePeriod is set, so slow-shutdown workloads can be granted
+// more time than grim-reaper would otherwise allow.
+func (p *podEvictor) Evict(ctx context.Context, pod *corev1.Pod) error {
+	ceiling := GracefulTerminationSeconds
+	if p.ExtendedGracePeriod {
+		ceiling = p.MaxGracePeriodSeconds
+	}
+
+	grace := ceiling
+	if pod.Spec.TerminationGracePeriodSeconds != nil && *pod.Spec.TerminationGracePeriodSeconds < grace {
+		grace = *pod.Spec.TerminationGracePeriodSeconds
+	}
+
+	objectMeta := metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}
+	deleteOptions := &metav1.DeleteOptions{GracePeriodSeconds: &grace}
+
+	evict := func() error {
+		if p.EvictionAPIVersion == "v1" {
+			return p.clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, &policyv1.Eviction{ObjectMeta: objectMeta, DeleteOptions: deleteOptions})
+		}
+		return p.clientset.PolicyV1beta1().Evictions(pod.Namespace).Evict(ctx, &policyv1beta1.Eviction{ObjectMeta: objectMeta, DeleteOptions: deleteOptions})
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := evict()
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !apierrors.IsTooManyRequests(err) {
+			return err
+		}
+
+		blockMsg := ""
+		if pdb := p.findBlockingPDB(ctx, pod); pdb != nil {
+			blockMsg = pdbBlockMessage(pdb)
+		}
+
+		if attempt >= p.MaxRetries {
+			if blockMsg != "" {
+				return fmt.Errorf("%s: %w", blockMsg, err)
+			}
+			return err
+		}
+
+		delay := p.Backoff.NextDelay(attempt)
+		if retryAfter, ok := apierrors.SuggestsClientDelay(err); ok {
+			delay = time.Duration(retryAfter) * time.Second
+		}
+		if blockMsg != "" {
+			log.Printf("evicting pod %s/%s: %s; retrying in %s", pod.Namespace, pod.Name, blockMsg, delay)
+		}
+		metrics.EvictionRetriesTotal.WithLabelValues(p.Backoff.Name()).Inc()
+		metrics.EvictionBackoffSeconds.WithLabelValues(p.Backoff.Name()).Observe(delay.Seconds())
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// Evict only confirms the API server accepted the eviction; waiting for
+	// the pod to actually disappear is the caller's job (see
+	// customDrainer, which tracks every pod on a node through one shared
+	// watch instead of each Evict call polling its own pod individually).
+	return nil
+}
+
+// Delete deletes pod directly, bypassing the eviction API entirely, honoring
+// gracePeriodSeconds. It's the fallback for a pod that can't be evicted
+// within the normal retry budget (a stuck PDB, a broken admission webhook),
+// mirroring `kubectl drain --disable-eviction` semantics.
+func (p *podEvictor) Delete(ctx context.Context, pod *corev1.Pod, gracePeriodSeconds int64) error {
+	return p.clientset.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: &gracePeriodSeconds})
+}
+
+// findBlockingPDB looks up the PodDisruptionBudget, if any, currently
+// disallowing pod's eviction, so a retry can log and report something more
+// actionable than a bare 429. Best-effort: a lookup failure just means the
+// retry proceeds without that context.
+func (p *podEvictor) findBlockingPDB(ctx context.Context, pod *corev1.Pod) *policyv1.PodDisruptionBudget {
+	list, err := p.clientset.PolicyV1().PodDisruptionBudgets(pod.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	for i := range list.Items {
+		pdb := &list.Items[i]
+		if pdbCoversPod(pdb, pod) && pdb.Status.DisruptionsAllowed == 0 {
+			return pdb
+		}
+	}
+	return nil
+}
+
+// pdbCoversPod reports whether pdb's selector matches pod.
+func pdbCoversPod(pdb *policyv1.PodDisruptionBudget, pod *corev1.Pod) bool {
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil || selector.Empty() {
+		return false
+	}
+	return selector.Matches(labels.Set(pod.Labels))
+}
+
+// pdbBlockMessage summarizes why pdb is blocking an eviction, giving the
+// current vs. desired healthy pod count so an operator can tell how close
+// it is to allowing one.
+func pdbBlockMessage(pdb *policyv1.PodDisruptionBudget) string {
+	return fmt.Sprintf("blocked by PDB %s/%s (%d/%d healthy)", pdb.Namespace, pdb.Name, pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy)
+}
+
+// GracefulTerminationSeconds is the ceiling grace period grim-reaper will
+// ever grant an evicted pod.
+var GracefulTerminationSeconds int64 = 30
+
+// WatchPodsOnNode returns a watch over every pod with spec.nodeName=nodeName.
+func (p *podEvictor) WatchPodsOnNode(ctx context.Context, nodeName string) (watch.Interface, error) {
+	return p.clientset.CoreV1().Pods("").Watch(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+}
@@ -0,0 +1,61 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/BrianKopp/grim-reaper/internal/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// nodeIsReady reports whether node's Ready condition is currently True.
+func nodeIsReady(node corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// checkReadyNodeFloor re-lists every node matching Config.NodeSelector
+// right before dispatching node's harvest, and stops the run if the
+// number that are currently Ready -- not counting node itself, which is
+// about to be removed -- would fall below Config.MinNodes. Without this,
+// MinNodes only ever counted nodes that existed, Ready or not, so a
+// cluster already limping along on a few NotReady nodes could have its
+// last healthy capacity reaped out from under it. No-op if
+// Config.MinNodesRequireReady is false.
+func (g *GrimReaper) checkReadyNodeFloor(ctx context.Context, node *corev1.Node) error {
+	if !g.Config.MinNodesRequireReady {
+		return nil
+	}
+
+	nodes, err := g.Nodes.ListNodes(ctx, g.Config.NodeSelector)
+	if err != nil {
+		log.Printf("ready-node floor check: listing nodes: %v", err)
+		return nil
+	}
+
+	ready := 0
+	for i := range nodes {
+		if nodeIsReady(nodes[i]) {
+			ready++
+		}
+	}
+	if nodeIsReady(*node) {
+		ready--
+	}
+
+	minNodes, err := config.ParseMinNodes(g.Config.MinNodes, len(nodes))
+	if err != nil {
+		log.Printf("ready-node floor check: parsing min-nodes: %v", err)
+		return nil
+	}
+
+	if ready < minNodes {
+		return fmt.Errorf("only %d Ready node(s) would remain out of %d matching the selector, below min-nodes=%s (%d); stopping before harvesting node %s", ready, len(nodes), g.Config.MinNodes, minNodes, node.Name)
+	}
+	return nil
+}
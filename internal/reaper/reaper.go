@@ -0,0 +1,1675 @@
+// Package reaper implements the core node-selection and harvesting logic
+// behind grim-reaper: pick nodes to retire, drain them, and delete them.
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/BrianKopp/grim-reaper/internal/cloud"
+	"github.com/BrianKopp/grim-reaper/internal/config"
+	"github.com/BrianKopp/grim-reaper/internal/ledger"
+	"github.com/BrianKopp/grim-reaper/internal/metrics"
+	"github.com/BrianKopp/grim-reaper/internal/notify"
+)
+
+// PassoverReasonBudget is recorded against nodes that were otherwise
+// eligible for reaping but lost out to MinNodes/MaxNodesDelete/ReapFraction
+// budget limits.
+const PassoverReasonBudget = "budget"
+
+// PassoverReasonDensity is recorded against nodes that were otherwise
+// eligible for reaping but exceeded MaxPodDensity.
+const PassoverReasonDensity = "density"
+
+// PassoverReasonYoungPods is recorded against nodes that were otherwise
+// eligible for reaping but hosted a pod younger than YoungPodGrace.
+const PassoverReasonYoungPods = "young-pods"
+
+// PassoverReasonStatefulBudget is recorded against nodes that were
+// otherwise eligible for reaping but would have pushed this run's total
+// displaced stateful data past MaxStatefulGBPerRun; they're left marked
+// for destruction, to resume on the next run.
+const PassoverReasonStatefulBudget = "stateful-budget"
+
+// ReapReason canonically explains why a node was selected for reaping, so
+// every node grim-reaper destroys has a single explanation that can be
+// traced through its annotation, its metrics label, and the notification
+// sent about it.
+type ReapReason string
+
+const (
+	// ReapReasonAge means the node was the longest- (or most recently-)
+	// lived candidate under an age-based strategy.
+	ReapReasonAge ReapReason = "age"
+	// ReapReasonUtilization means the node was selected by a
+	// utilization-based strategy (emptiest, fragmentation, most-restarts).
+	ReapReasonUtilization ReapReason = "utilization"
+	// ReapReasonTTL means the node exceeded MaxNodeAge.
+	ReapReasonTTL ReapReason = "ttl"
+	// ReapReasonNotReady means the node was picked up by the NotReady
+	// auto-repair budget, not the regular selection pipeline.
+	ReapReasonNotReady ReapReason = "not-ready"
+	// ReapReasonManualAnnotation means the node carried ForceReapAnnotation.
+	ReapReasonManualAnnotation ReapReason = "manual-annotation"
+	// ReapReasonImageRollout means the node's kubelet version or OS image
+	// no longer matches TargetKubeletVersion/TargetImageRegex.
+	ReapReasonImageRollout ReapReason = "image-rollout"
+)
+
+// ReapReasonAnnotation persists the ReapReason a node was selected under.
+// Selection only holds the node in memory, well before MarkNodesForDestruction
+// and Harvest run against it later (possibly after a restart), so the reason
+// has to live on the node object itself to survive that gap.
+const ReapReasonAnnotation = "grim-reaper.io/reap-reason"
+
+// setReapReason records reason in node's in-memory annotations. It does not
+// talk to the API server; callers persist it later via NodeInterface.Annotate
+// once the node is actually marked for destruction.
+func setReapReason(node *corev1.Node, reason ReapReason) {
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[ReapReasonAnnotation] = string(reason)
+}
+
+// reapReason returns the ReapReason recorded on node, or "unknown" if
+// selection never tagged it.
+func reapReason(node *corev1.Node) string {
+	if reason := node.Annotations[ReapReasonAnnotation]; reason != "" {
+		return reason
+	}
+	return "unknown"
+}
+
+// strategyReapReason reports the ReapReason that best describes strategy's
+// ordering, for tagging fraction-budget picks that weren't forced by a more
+// specific criterion.
+func strategyReapReason(strategy string) ReapReason {
+	switch strategy {
+	case "", "oldest", "newest":
+		return ReapReasonAge
+	default:
+		return ReapReasonUtilization
+	}
+}
+
+// DefaultPassoverEscalationThreshold is how many times a node can be
+// passed over for the same reason before GrimReaper escalates.
+const DefaultPassoverEscalationThreshold = 5
+
+// HarvestFailureCountAnnotation and HarvestLastErrorAnnotation remember, on
+// the node itself, how many consecutive times harvesting it has failed and
+// why, so grim-reaper can recognize a cursed node across runs (and restarts)
+// instead of retrying it forever.
+const (
+	HarvestFailureCountAnnotation = "grim-reaper.io/harvest-failure-count"
+	HarvestLastErrorAnnotation    = "grim-reaper.io/harvest-last-error"
+)
+
+// errHarvestAbandoned wraps the error returned by Harvest when a node has
+// exceeded MaxHarvestFailures and is no longer being retried automatically.
+var errHarvestAbandoned = fmt.Errorf("node abandoned after repeated harvest failures")
+
+// ProtectedAnnotation lets an operator exempt a specific node from reaping
+// without having to change NodeSelector. A node carrying
+// ProtectedAnnotation="true" is dropped before selection ever sees it.
+const ProtectedAnnotation = "grim-reaper.io/protected"
+
+// filterProtected removes nodes carrying ProtectedAnnotation from nodes.
+func filterProtected(nodes []corev1.Node) []corev1.Node {
+	filtered := nodes[:0:0]
+	for _, node := range nodes {
+		if node.Annotations[ProtectedAnnotation] == "true" {
+			log.Printf("node %s is protected (%s=true), skipping", node.Name, ProtectedAnnotation)
+			continue
+		}
+		filtered = append(filtered, node)
+	}
+	return filtered
+}
+
+// filterDense removes nodes hosting more evictable (non-DaemonSet) pods than
+// MaxPodDensity, recording a PassoverReasonDensity passover for each one,
+// since draining a very dense node risks a rescheduling storm better
+// handled during dedicated maintenance than an automated run.
+func (g *GrimReaper) filterDense(ctx context.Context, nodes []corev1.Node) []corev1.Node {
+	if g.Config.MaxPodDensity <= 0 {
+		return nodes
+	}
+
+	var allowed, dense []corev1.Node
+	for _, node := range nodes {
+		pods, err := g.Pods.ListPodsOnNode(ctx, node.Name)
+		if err != nil {
+			log.Printf("checking pod density for node %s: %v", node.Name, err)
+			allowed = append(allowed, node)
+			continue
+		}
+		evictable := 0
+		for i := range pods {
+			if !isDaemonSetPod(pods[i]) {
+				evictable++
+			}
+		}
+		if evictable > g.Config.MaxPodDensity {
+			dense = append(dense, node)
+			continue
+		}
+		allowed = append(allowed, node)
+	}
+	g.recordPassovers(dense, PassoverReasonDensity)
+	return allowed
+}
+
+// filterYoungPods removes nodes hosting a pod younger than YoungPodGrace,
+// recording a PassoverReasonYoungPods passover for each one, since evicting
+// a pod that just started churns a workload that may still be warming
+// caches or registering with load balancers.
+func (g *GrimReaper) filterYoungPods(ctx context.Context, nodes []corev1.Node) []corev1.Node {
+	if g.Config.YoungPodGrace <= 0 {
+		return nodes
+	}
+
+	var allowed, young []corev1.Node
+	for _, node := range nodes {
+		pods, err := g.Pods.ListPodsOnNode(ctx, node.Name)
+		if err != nil {
+			log.Printf("checking pod age for node %s: %v", node.Name, err)
+			allowed = append(allowed, node)
+			continue
+		}
+
+		hasYoungPod := false
+		for _, pod := range pods {
+			if time.Since(pod.CreationTimestamp.Time) < g.Config.YoungPodGrace {
+				hasYoungPod = true
+				break
+			}
+		}
+		if hasYoungPod {
+			young = append(young, node)
+			continue
+		}
+		allowed = append(allowed, node)
+	}
+	g.recordPassovers(young, PassoverReasonYoungPods)
+	return allowed
+}
+
+// PassoverReasonPDBInfeasible is recorded against nodes that were
+// otherwise eligible for reaping but host a pod covered by a structurally
+// infeasible PodDisruptionBudget (e.g. maxUnavailable 0, or a single
+// replica required to stay fully healthy), so eviction could never
+// succeed and leaving the node half-drained and cordoned is worse than
+// just passing it over.
+const PassoverReasonPDBInfeasible = "pdb-infeasible"
+
+// filterPDBInfeasible removes nodes hosting a pod covered by a PDB that
+// can never allow a disruption given its current replica count, recording
+// a PassoverReasonPDBInfeasible passover for each one. Catching this
+// before cordoning saves a doomed drain attempt that would otherwise only
+// time out mid-eviction with the node left half-cordoned.
+func (g *GrimReaper) filterPDBInfeasible(ctx context.Context, nodes []corev1.Node) []corev1.Node {
+	if !g.Config.PDBFeasibilityCheck || g.PDBs == nil {
+		return nodes
+	}
+
+	pdbsByNamespace := map[string][]policyv1.PodDisruptionBudget{}
+	var allowed, infeasible []corev1.Node
+	for _, node := range nodes {
+		pods, err := g.Pods.ListPodsOnNode(ctx, node.Name)
+		if err != nil {
+			log.Printf("checking PDB feasibility for node %s: %v", node.Name, err)
+			allowed = append(allowed, node)
+			continue
+		}
+
+		blocked := false
+		for i := range pods {
+			pod := &pods[i]
+			pdbs, ok := pdbsByNamespace[pod.Namespace]
+			if !ok {
+				pdbs, err = g.PDBs.ListPDBs(ctx, pod.Namespace)
+				if err != nil {
+					log.Printf("listing PDBs in namespace %s: %v", pod.Namespace, err)
+					continue
+				}
+				pdbsByNamespace[pod.Namespace] = pdbs
+			}
+			for j := range pdbs {
+				pdb := &pdbs[j]
+				if pdbCoversPod(pdb, pod) && pdbStructurallyInfeasible(pdb) {
+					blocked = true
+					log.Printf("node %s hosts pod %s/%s covered by PDB %s, which requires %d/%d pods healthy; eviction can never succeed, skipping",
+						node.Name, pod.Namespace, pod.Name, pdb.Name, pdb.Status.DesiredHealthy, pdb.Status.ExpectedPods)
+					break
+				}
+			}
+			if blocked {
+				break
+			}
+		}
+
+		if blocked {
+			infeasible = append(infeasible, node)
+			continue
+		}
+		allowed = append(allowed, node)
+	}
+	g.recordPassovers(infeasible, PassoverReasonPDBInfeasible)
+	return allowed
+}
+
+// pdbStructurallyInfeasible reports whether pdb's spec leaves no slack for
+// a disruption regardless of timing: every pod it covers must stay
+// healthy (maxUnavailable 0, or minAvailable equal to its replica count,
+// as with a single-replica workload requiring minAvailable 1), so an
+// eviction can never succeed without a human first scaling the workload
+// up or relaxing the budget.
+func pdbStructurallyInfeasible(pdb *policyv1.PodDisruptionBudget) bool {
+	return pdb.Status.ExpectedPods > 0 && pdb.Status.ExpectedPods <= pdb.Status.DesiredHealthy
+}
+
+// GrimReaper ties node selection and harvesting together against a single
+// cluster.
+type GrimReaper struct {
+	Nodes    NodeInterface
+	Pods     PodEvictor
+	Config   *config.Config
+	Notifier *notify.Router
+
+	// Namespaces looks up namespace objects to enforce tenant-tier
+	// eviction policy (see AllowedTenantTiers). Nil skips the check
+	// entirely, as if every namespace's tier were allowed.
+	Namespaces NamespaceInterface
+
+	// PVCs looks up PersistentVolumeClaim storage sizes, so Run can
+	// estimate the re-replication/attachment time stateful workloads
+	// add to a drain and enforce MaxStatefulGBPerRun. Nil disables
+	// stateful-data accounting entirely, as if no pod used a PVC.
+	PVCs PVCInterface
+
+	// Cloud is consulted before draining (to protect the instance from
+	// scale-in) and after deleting the node (to terminate it). It defaults
+	// to cloud.NoopProvider{} when unset.
+	Cloud cloud.Provider
+
+	// ProviderIDToInstanceID extracts the instance ID that Cloud
+	// understands from node.Spec.ProviderID.
+	ProviderIDToInstanceID  func(providerID string) (string, error)
+	CloudTerminationTimeout time.Duration
+
+	// AsyncTerminator, if set, hands off instance termination to a
+	// background queue once a node is drained and deleted, instead of
+	// blocking Harvest on Cloud.TerminateInstance/WaitForTermination. Nil
+	// falls back to terminating synchronously, as before.
+	AsyncTerminator *AsyncTerminator
+
+	// Ledger, if set, persists passovers so repeatedly-skipped nodes can be
+	// escalated once PassoverEscalationThreshold is exceeded.
+	Ledger                      ledger.Ledger
+	PassoverEscalationThreshold int
+
+	// DisruptionReader, if set, reports how many node disruptions other
+	// tools have recorded recently, so GetNodesToReap can count them
+	// against MaxNodesDelete via ExternalDisruptionWindow. Nil disables
+	// the check entirely, as if no other tool had disrupted anything.
+	DisruptionReader ledger.DisruptionReader
+
+	// externalDisruptions is the count DisruptionReader reported for the
+	// run currently in progress. Computed once per GetNodesToReap call and
+	// reused across every group's selectFromPool, so a node-group split
+	// doesn't let each group spend the same external budget independently.
+	externalDisruptions int
+
+	// Drainer performs the actual pod eviction for a node. It defaults to
+	// a customDrainer built from Pods when unset.
+	Drainer Drainer
+
+	// Recorder, if set, emits Events against the Node objects grim-reaper
+	// cordons, drains, and deletes, so cluster operators can see reaper
+	// activity in `kubectl describe node` and their event pipeline. Emits
+	// nothing when unset.
+	Recorder record.EventRecorder
+
+	// PassoverObserver, if set, is called once for every node Run passes
+	// over during selection, with the node's name and the reason it was
+	// skipped. Run uses this to populate LastReport.PassedOver; set it
+	// directly only for testing.
+	PassoverObserver func(nodeName, reason string)
+
+	// LastReport holds the structured outcome of the most recent Run
+	// call, for callers that want to write it out (see WriteReport) once
+	// the run completes. Replaced wholesale at the start of every Run
+	// call; nil until Run has run at least once.
+	LastReport *RunReport
+
+	// StopRequested, if set, is polled once per iteration of Run's
+	// harvest loop, before starting the next node. The moment it reports
+	// true, Run stops starting new harvests and returns
+	// ErrShutdownRequested, without interrupting whichever node is
+	// already mid-harvest. Nil means never stop early.
+	StopRequested func() bool
+
+	// currentNodes tracks the nodes Run is actively harvesting, up to
+	// Config.MaxConcurrentNodeDrains at once. Guarded by currentNodesMu
+	// since concurrent harvests mutate it from separate goroutines. Use
+	// CurrentNodes to read it. Exposed so /healthz and /readyz can report
+	// in-progress drain state without polling the API server.
+	currentNodesMu sync.Mutex
+	currentNodes   map[string]bool
+
+	// Events looks up Kubernetes Events involving a harvested node and
+	// its pods, for the run report's CollectEventCorrelation summary (see
+	// config.Config.CollectEventCorrelation). Nil disables the feature
+	// entirely, as if it were turned off.
+	Events EventReader
+
+	// PDBs looks up PodDisruptionBudgets for the pre-drain feasibility
+	// check (see config.Config.PDBFeasibilityCheck). Nil disables the
+	// check entirely, as if every PDB could always be satisfied.
+	PDBs PDBInterface
+
+	// Alerts looks up currently-firing alerts for post-harvest
+	// verification (see config.Config.PostHarvestVerification). Nil
+	// disables that specific check, as if no alerts were ever firing.
+	Alerts AlertGate
+
+	// replacementFailuresMu guards consecutiveReplacementFailures, which
+	// counts harvests in a row whose evicted pods' replacements failed to
+	// become Ready within Config.ReplacementReadyTimeout. See
+	// awaitReplacementsReady.
+	replacementFailuresMu         sync.Mutex
+	consecutiveReplacementFailures int
+}
+
+// ErrShutdownRequested is returned by Run when StopRequested reports true
+// before every selected node has been harvested.
+var ErrShutdownRequested = fmt.Errorf("shutdown requested")
+
+// markHarvesting records that node is actively being harvested, for
+// CurrentNodes.
+func (g *GrimReaper) markHarvesting(node string) {
+	g.currentNodesMu.Lock()
+	defer g.currentNodesMu.Unlock()
+	if g.currentNodes == nil {
+		g.currentNodes = map[string]bool{}
+	}
+	g.currentNodes[node] = true
+}
+
+// unmarkHarvesting records that node is no longer being harvested.
+func (g *GrimReaper) unmarkHarvesting(node string) {
+	g.currentNodesMu.Lock()
+	defer g.currentNodesMu.Unlock()
+	delete(g.currentNodes, node)
+}
+
+// CurrentNodes returns the names of every node Run is actively harvesting
+// right now, up to Config.MaxConcurrentNodeDrains at once.
+func (g *GrimReaper) CurrentNodes() []string {
+	g.currentNodesMu.Lock()
+	defer g.currentNodesMu.Unlock()
+	names := make([]string, 0, len(g.currentNodes))
+	for name := range g.currentNodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// recordEvent emits an Event against node if g.Recorder is set, a no-op
+// otherwise.
+func (g *GrimReaper) recordEvent(node *corev1.Node, eventType, reason, messageFmt string, args ...interface{}) {
+	if g.Recorder == nil {
+		return
+	}
+	g.Recorder.Eventf(node, eventType, reason, messageFmt, args...)
+}
+
+// New returns a GrimReaper wired up with the given dependencies.
+func New(nodes NodeInterface, pods PodEvictor, cfg *config.Config) *GrimReaper {
+	return &GrimReaper{Nodes: nodes, Pods: pods, Config: cfg, Cloud: cloud.NoopProvider{}}
+}
+
+// GetNodesToReap returns the nodes this run should reap, ordered by
+// strategy and bounded by MinNodes / MaxNodesDelete / ReapFraction. Nodes
+// already marked for destruction by a previous, possibly crashed, run are
+// always included first, ahead of (and uncounted against the budget of)
+// anything freshly selected, so a restart finishes what it started instead
+// of leaving them half-cordoned forever.
+func (g *GrimReaper) GetNodesToReap(ctx context.Context) ([]corev1.Node, error) {
+	nodes, err := g.Nodes.ListNodes(ctx, g.Config.NodeSelector)
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+	allNodes := nodes
+
+	resuming, nodes := splitMarkedForDestruction(nodes)
+	resuming = g.gcStaleMarkers(ctx, resuming)
+	if len(resuming) > 0 {
+		log.Printf("resuming harvest of %d node(s) already marked for destruction by a previous run", len(resuming))
+	}
+
+	nodes = filterProtected(nodes)
+	nodes = g.filterDense(ctx, nodes)
+	nodes = g.filterYoungPods(ctx, nodes)
+	nodes = g.filterPDBInfeasible(ctx, nodes)
+
+	g.externalDisruptions = g.countExternalDisruptions()
+
+	var selected []corev1.Node
+	if g.Config.NodeGroupLabel == "" {
+		selected, err = g.selectFromPool(ctx, nodes)
+	} else {
+		selected, err = g.selectAcrossGroups(ctx, nodes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	selected = g.filterCapacityHeadroom(ctx, allNodes, selected)
+	selected = g.filterSchedulingFeasibility(ctx, allNodes, selected)
+	return append(resuming, selected...), nil
+}
+
+// splitMarkedForDestruction pulls out nodes already cordoned and tagged
+// with ReapReasonAnnotation by a previous run's MarkNodesForDestruction --
+// the two things GetNodesToReap does to a node before handing it to
+// Harvest. Seeing both together means a previous run committed to
+// harvesting this node and didn't finish, rather than an operator manually
+// cordoning it for unrelated maintenance.
+func splitMarkedForDestruction(nodes []corev1.Node) (marked, rest []corev1.Node) {
+	for i := range nodes {
+		if nodes[i].Spec.Unschedulable && nodes[i].Annotations[ReapReasonAnnotation] != "" {
+			marked = append(marked, nodes[i])
+		} else {
+			rest = append(rest, nodes[i])
+		}
+	}
+	return marked, rest
+}
+
+// nodeOrphanCordoned reports whether node is cordoned by something other
+// than grim-reaper -- an "orphaned cordon" left over from manual
+// intervention or another tool, rather than ReapReasonAnnotation-tagged
+// work grim-reaper committed to itself. Warmup surfaces these for visibility;
+// MaxUnavailable counts them against its budget, since they're already
+// unavailable capacity whether or not grim-reaper put them there.
+func nodeOrphanCordoned(node *corev1.Node) bool {
+	return node.Spec.Unschedulable && node.Annotations[ReapReasonAnnotation] == ""
+}
+
+// gcStaleMarkers drops, from marked, any node whose MarkedAtAnnotation is
+// older than StaleMarkerTTL, uncordoning it and clearing its markers
+// instead of resuming the harvest. This bounds how long a run that died
+// mid-harvest can leave a node unschedulable: past the TTL it's treated as
+// abandoned, not merely slow. A no-op when StaleMarkerTTL is 0.
+func (g *GrimReaper) gcStaleMarkers(ctx context.Context, marked []corev1.Node) []corev1.Node {
+	if g.Config.StaleMarkerTTL <= 0 {
+		return marked
+	}
+
+	fresh := marked[:0:0]
+	for i := range marked {
+		if !markerIsStale(&marked[i], g.Config.StaleMarkerTTL) {
+			fresh = append(fresh, marked[i])
+			continue
+		}
+
+		log.Printf("node %s has been marked for destruction since %s, past --stale-marker-ttl; uncordoning and clearing its markers", marked[i].Name, marked[i].Annotations[MarkedAtAnnotation])
+		if err := g.ClearDestructionMarkers(ctx, &marked[i], "StaleMarkerCleared", "grim-reaper abandoned this node's harvest after --stale-marker-ttl elapsed and uncordoned it"); err != nil {
+			log.Printf("clearing markers on stale node %s: %v", marked[i].Name, err)
+			fresh = append(fresh, marked[i])
+		}
+	}
+	return fresh
+}
+
+// markerIsStale reports whether node's MarkedAtAnnotation is older than
+// ttl. A missing or unparsable timestamp is treated as fresh, since that
+// indicates a marker grim-reaper itself never finished writing, not one it
+// safely abandoned.
+func markerIsStale(node *corev1.Node, ttl time.Duration) bool {
+	markedAt, err := time.Parse(time.RFC3339, node.Annotations[MarkedAtAnnotation])
+	return err == nil && time.Since(markedAt) > ttl
+}
+
+// ClearDestructionMarkers uncordons node and removes every trace of it
+// having been marked for destruction: the reap-reason/marked-at/marked-by
+// annotations, harvest-failure bookkeeping, any configured ReapTaint, and
+// the Reaping node condition (set to False with reason and message). Used
+// both by the automatic stale-marker GC and by the `uncordon` subcommand's
+// manual rollback of a partial run.
+func (g *GrimReaper) ClearDestructionMarkers(ctx context.Context, node *corev1.Node, reason, message string) error {
+	if err := g.Nodes.Uncordon(ctx, node); err != nil {
+		return fmt.Errorf("uncordoning node %s: %w", node.Name, err)
+	}
+	if err := g.Nodes.Annotate(ctx, node, map[string]interface{}{
+		ReapReasonAnnotation:          nil,
+		MarkedAtAnnotation:            nil,
+		MarkedByAnnotation:            nil,
+		HarvestFailureCountAnnotation: nil,
+		HarvestLastErrorAnnotation:    nil,
+	}); err != nil {
+		log.Printf("clearing markers on node %s: %v", node.Name, err)
+	}
+	if g.Config.ReapTaint != "" {
+		if key, _, err := config.ParseReapTaint(g.Config.ReapTaint); err == nil {
+			if err := g.Nodes.Untaint(ctx, node, key); err != nil {
+				log.Printf("clearing reap taint on node %s: %v", node.Name, err)
+			}
+		}
+	}
+	condition := corev1.NodeCondition{
+		Type:               ReapingConditionType,
+		Status:             corev1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	}
+	if err := g.Nodes.SetCondition(ctx, node, condition); err != nil {
+		log.Printf("clearing reaping condition on node %s: %v", node.Name, err)
+	}
+	return nil
+}
+
+// UncordonMarkedNodes finds every node currently marked for destruction
+// (cordoned with a ReapReasonAnnotation, whether mid-harvest or abandoned
+// by a crashed or aborted run) and reverts it via ClearDestructionMarkers.
+// It returns the names of every node successfully reverted, and the first
+// error encountered continuing on to the rest rather than stopping early,
+// so one bad node doesn't block the rollback of the others.
+func (g *GrimReaper) UncordonMarkedNodes(ctx context.Context, reason, message string) ([]string, error) {
+	nodes, err := g.Nodes.ListNodes(ctx, g.Config.NodeSelector)
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	marked, _ := splitMarkedForDestruction(nodes)
+	var reverted []string
+	var firstErr error
+	for i := range marked {
+		if err := g.ClearDestructionMarkers(ctx, &marked[i], reason, message); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		reverted = append(reverted, marked[i].Name)
+	}
+	return reverted, firstErr
+}
+
+// selectAcrossGroups groups nodes by NodeGroupLabel and runs selectFromPool
+// independently within each group, so MinNodes/MaxNodesDelete/ReapFraction
+// are enforced per group rather than across the cluster as a whole. The
+// per-group results are then merged round-robin, so the aggregate reap
+// list draws evenly from every group instead of exhausting one group's
+// quota before ever reaching the next.
+func (g *GrimReaper) selectAcrossGroups(ctx context.Context, nodes []corev1.Node) ([]corev1.Node, error) {
+	groups := map[string][]corev1.Node{}
+	var groupKeys []string
+	for _, node := range nodes {
+		key := node.Labels[g.Config.NodeGroupLabel]
+		if _, ok := groups[key]; !ok {
+			groupKeys = append(groupKeys, key)
+		}
+		groups[key] = append(groups[key], node)
+	}
+
+	selectedByGroup := make(map[string][]corev1.Node, len(groups))
+	for _, key := range groupKeys {
+		selected, err := g.selectFromPool(ctx, groups[key])
+		if err != nil {
+			return nil, fmt.Errorf("selecting within node group %q: %w", key, err)
+		}
+		selectedByGroup[key] = selected
+	}
+
+	var merged []corev1.Node
+	for i := 0; ; i++ {
+		added := false
+		for _, key := range groupKeys {
+			bucket := selectedByGroup[key]
+			if i < len(bucket) {
+				merged = append(merged, bucket[i])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return merged, nil
+}
+
+// countExternalDisruptions returns how many disruptions DisruptionReader
+// has recorded within ExternalDisruptionWindow, or 0 if the feature is
+// disabled or the reader fails -- a read error here should not block a
+// run, it just means the external budget isn't enforced this cycle.
+func (g *GrimReaper) countExternalDisruptions() int {
+	if !g.Config.ExternalDisruptionLedgerEnabled || g.DisruptionReader == nil {
+		return 0
+	}
+	count, err := g.DisruptionReader.CountRecent(g.Config.ExternalDisruptionWindow)
+	if err != nil {
+		log.Printf("reading external disruption ledger: %v", err)
+		return 0
+	}
+	return count
+}
+
+// selectFromPool runs the full selection pipeline -- ordering, forced
+// criteria, the MinNodes/MaxNodesDelete/MaxUnavailable/ReapFraction budget,
+// NotReady auto-repair, and zone-balance -- against nodes as if it were the
+// whole cluster. Called once against the full node list, or once per group
+// when NodeGroupLabel splits the cluster into independently-budgeted
+// groups.
+func (g *GrimReaper) selectFromPool(ctx context.Context, nodes []corev1.Node) ([]corev1.Node, error) {
+	selector, err := g.selector()
+	if err != nil {
+		return nil, err
+	}
+	ordered := selector.Order(ctx, nodes, g.Pods)
+	ordered = deprioritizeSelf(ordered, g.Config.SelfNodeName)
+	ordered = g.preferFree(ctx, ordered)
+
+	minNodes, err := config.ParseMinNodes(g.Config.MinNodes, len(nodes))
+	if err != nil {
+		return nil, err
+	}
+	hardCap := len(nodes) - minNodes
+	if hardCap < 0 {
+		hardCap = 0
+	}
+	maxNodesDelete := g.Config.MaxNodesDelete - g.externalDisruptions
+	if maxNodesDelete < 0 {
+		maxNodesDelete = 0
+	}
+	if hardCap > maxNodesDelete {
+		hardCap = maxNodesDelete
+	}
+
+	if g.Config.MaxUnavailable != "" {
+		budget, err := config.ParseMaxUnavailable(g.Config.MaxUnavailable, len(nodes))
+		if err != nil {
+			return nil, err
+		}
+		orphanCordoned := 0
+		for i := range nodes {
+			if nodeOrphanCordoned(&nodes[i]) {
+				orphanCordoned++
+			}
+		}
+		unavailableCap := budget - orphanCordoned
+		if unavailableCap < 0 {
+			unavailableCap = 0
+		}
+		if hardCap > unavailableCap {
+			hardCap = unavailableCap
+		}
+	}
+
+	// Nodes older than MaxNodeAge, or running a stale kubelet version or
+	// node image, are reaped independent of the fraction-based budget
+	// below, so a node-recycling or rolling-upgrade policy is enforced
+	// even during a run where ReapFraction alone would select few or no
+	// nodes. They still respect MinNodes/MaxNodesDelete via hardCap.
+	isForced, err := g.forcedReapPredicate()
+	if err != nil {
+		return nil, err
+	}
+	forced, rest := splitForced(ordered, isForced)
+
+	selectedForced := forced
+	if len(selectedForced) > hardCap {
+		selectedForced = selectedForced[:hardCap]
+	}
+
+	fractionCount := int(float64(len(nodes)) * g.Config.ReapFraction)
+	remainingBudget := hardCap - len(selectedForced)
+	if remainingBudget > fractionCount {
+		remainingBudget = fractionCount
+	}
+	if remainingBudget > len(rest) {
+		remainingBudget = len(rest)
+	}
+	if remainingBudget < 0 {
+		remainingBudget = 0
+	}
+	selectedRest := rest[:remainingBudget]
+	strategyReason := strategyReapReason(g.Config.Strategy)
+	for i := range selectedRest {
+		setReapReason(&selectedRest[i], strategyReason)
+	}
+
+	passedOver := append([]corev1.Node{}, forced[len(selectedForced):]...)
+	passedOver = append(passedOver, rest[remainingBudget:]...)
+	g.recordPassovers(passedOver, PassoverReasonBudget)
+
+	selected := append(selectedForced, selectedRest...)
+	notReady := g.notReadySelection(ordered, selected, len(nodes), minNodes)
+	for i := range notReady {
+		setReapReason(&notReady[i], ReapReasonNotReady)
+	}
+	selected = append(selected, notReady...)
+	selected = g.enforceZoneBalance(nodes, selected)
+	return selected, nil
+}
+
+// ZoneLabel is the well-known label identifying which availability zone a
+// node is in.
+const ZoneLabel = "topology.kubernetes.io/zone"
+
+// PassoverReasonZoneBalance is recorded against nodes that were otherwise
+// eligible for reaping but would have pushed their zone's node count more
+// than ZoneBalanceMaxSkew below the other zones.
+const PassoverReasonZoneBalance = "zone-balance"
+
+// enforceZoneBalance drops any node from selected whose removal would leave
+// its zone with more than ZoneBalanceMaxSkew fewer nodes than some other
+// zone, so a reap run can't quietly hollow out one availability zone. all
+// is the full candidate pool used to establish each zone's current node
+// count. Disabled (returns selected unchanged) when ZoneBalanceMaxSkew <= 0
+// or the cluster has only one zone.
+func (g *GrimReaper) enforceZoneBalance(all, selected []corev1.Node) []corev1.Node {
+	if g.Config.ZoneBalanceMaxSkew <= 0 {
+		return selected
+	}
+
+	zoneCounts := map[string]int{}
+	for _, node := range all {
+		zoneCounts[node.Labels[ZoneLabel]]++
+	}
+	if len(zoneCounts) <= 1 {
+		return selected
+	}
+
+	var allowed, blocked []corev1.Node
+	for _, node := range selected {
+		zone := node.Labels[ZoneLabel]
+		if zoneBalanceViolated(zoneCounts, zone, g.Config.ZoneBalanceMaxSkew) {
+			blocked = append(blocked, node)
+			continue
+		}
+		zoneCounts[zone]--
+		allowed = append(allowed, node)
+	}
+	g.recordPassovers(blocked, PassoverReasonZoneBalance)
+	return allowed
+}
+
+// zoneBalanceViolated reports whether removing one more node from zone
+// would leave it more than maxSkew nodes behind some other zone.
+func zoneBalanceViolated(zoneCounts map[string]int, zone string, maxSkew int) bool {
+	after := zoneCounts[zone] - 1
+	for other, count := range zoneCounts {
+		if other == zone {
+			continue
+		}
+		if count-after > maxSkew {
+			return true
+		}
+	}
+	return false
+}
+
+// notReadySelection returns nodes whose Ready condition has been
+// False/Unknown for longer than NotReadyGrace, not already in selected, up
+// to NotReadyMaxNodesDelete -- a budget kept separate from
+// MaxNodesDelete/ReapFraction so node auto-repair isn't starved by (or
+// doesn't starve) the regular recycling budget. minNodes (the caller's
+// already-resolved MinNodes floor) is still respected across both budgets
+// combined.
+func (g *GrimReaper) notReadySelection(ordered, selected []corev1.Node, totalNodes, minNodes int) []corev1.Node {
+	if g.Config.NotReadyGrace <= 0 {
+		return nil
+	}
+
+	already := make(map[string]bool, len(selected))
+	for i := range selected {
+		already[selected[i].Name] = true
+	}
+
+	budget := g.Config.NotReadyMaxNodesDelete
+	if remainingToMinNodes := totalNodes - minNodes - len(selected); remainingToMinNodes < budget {
+		budget = remainingToMinNodes
+	}
+	if budget <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	var extra []corev1.Node
+	for i := range ordered {
+		if len(extra) >= budget {
+			break
+		}
+		if already[ordered[i].Name] {
+			continue
+		}
+		if notReadyDuration(ordered[i], now) > g.Config.NotReadyGrace {
+			extra = append(extra, ordered[i])
+		}
+	}
+	return extra
+}
+
+// notReadyDuration returns how long node's Ready condition has been
+// False or Unknown, or 0 if it's currently Ready or has no Ready
+// condition reported at all.
+func notReadyDuration(node corev1.Node, now time.Time) time.Duration {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type != corev1.NodeReady {
+			continue
+		}
+		if cond.Status == corev1.ConditionTrue {
+			return 0
+		}
+		return now.Sub(cond.LastTransitionTime.Time)
+	}
+	return 0
+}
+
+// ForceReapAnnotation lets an operator queue a specific node for the next
+// run's reaping regardless of its age, moving it into the forced group
+// splitForced already carves out for MaxNodeAge/kubelet/image criteria.
+const ForceReapAnnotation = "grim-reaper.io/reap"
+
+// forcedReapPredicate combines every "reap regardless of ReapFraction"
+// criterion (max node age, stale kubelet version, stale node image,
+// ForceReapAnnotation) into a single predicate for splitForced, tagging each
+// match with the ReapReason that justified it.
+func (g *GrimReaper) forcedReapPredicate() (func(corev1.Node) (bool, ReapReason), error) {
+	var imageRegex *regexp.Regexp
+	if g.Config.TargetImageRegex != "" {
+		compiled, err := regexp.Compile(g.Config.TargetImageRegex)
+		if err != nil {
+			return nil, fmt.Errorf("compiling --target-image-regex: %w", err)
+		}
+		imageRegex = compiled
+	}
+
+	now := time.Now()
+	return func(node corev1.Node) (bool, ReapReason) {
+		if node.Annotations[ForceReapAnnotation] == "true" {
+			return true, ReapReasonManualAnnotation
+		}
+		if g.Config.MaxNodeAge > 0 && now.Sub(node.CreationTimestamp.Time) > g.Config.MaxNodeAge {
+			return true, ReapReasonTTL
+		}
+		if g.Config.TargetKubeletVersion != "" && node.Status.NodeInfo.KubeletVersion != g.Config.TargetKubeletVersion {
+			return true, ReapReasonImageRollout
+		}
+		if imageRegex != nil && !imageRegex.MatchString(node.Status.NodeInfo.OSImage) {
+			return true, ReapReasonImageRollout
+		}
+		return false, ""
+	}, nil
+}
+
+// splitForced partitions ordered into nodes that must be reaped regardless
+// of the fraction-based budget (per isForced) and the rest, preserving the
+// relative order from the selection strategy within each group. Nodes
+// isForced selects are tagged with the ReapReason it returns.
+func splitForced(ordered []corev1.Node, isForced func(corev1.Node) (bool, ReapReason)) (forced, rest []corev1.Node) {
+	for i := range ordered {
+		if forcedBy, reason := isForced(ordered[i]); forcedBy {
+			setReapReason(&ordered[i], reason)
+			forced = append(forced, ordered[i])
+		} else {
+			rest = append(rest, ordered[i])
+		}
+	}
+	return forced, rest
+}
+
+// selector returns the Selector for this run's configured strategy. The
+// "weighted" strategy is built directly from the configured score weights
+// rather than through NewSelector, since it needs parameters beyond a
+// strategy name.
+func (g *GrimReaper) selector() (Selector, error) {
+	if g.Config.Strategy == "weighted" {
+		return weightedSelector{weights: ScoreWeights{
+			Age:        g.Config.ScoreWeightAge,
+			PodCount:   g.Config.ScoreWeightPodCount,
+			Restarts:   g.Config.ScoreWeightRestarts,
+			Cordoned:   g.Config.ScoreWeightCordoned,
+			Annotation: g.Config.ScoreWeightAnnotation,
+		}}, nil
+	}
+	return NewSelector(g.Config.Strategy)
+}
+
+// recordPassovers persists a passover entry for each node and escalates
+// (via the notifier) any that have now exceeded PassoverEscalationThreshold
+// for the same reason.
+func (g *GrimReaper) recordPassovers(nodes []corev1.Node, reason string) {
+	if g.PassoverObserver != nil {
+		for i := range nodes {
+			g.PassoverObserver(nodes[i].Name, reason)
+		}
+	}
+
+	if g.Ledger == nil {
+		return
+	}
+
+	threshold := g.PassoverEscalationThreshold
+	if threshold <= 0 {
+		threshold = DefaultPassoverEscalationThreshold
+	}
+
+	for i := range nodes {
+		count, err := g.Ledger.Record(nodes[i].Name, reason)
+		if err != nil {
+			log.Printf("recording passover for node %s: %v", nodes[i].Name, err)
+			continue
+		}
+		if count < threshold {
+			continue
+		}
+		message := fmt.Sprintf("node %s has been passed over for %q %d times; it may need manual review", nodes[i].Name, reason, count)
+		log.Print(message)
+		if g.Notifier != nil {
+			if err := g.Notifier.NotifyEviction("", message); err != nil {
+				log.Printf("escalating passover for node %s: %v", nodes[i].Name, err)
+			}
+		}
+	}
+}
+
+// deprioritizeSelf moves selfNodeName, if present in ordered, to the very
+// end of the slice. grim-reaper runs as a pod on the cluster it's reaping;
+// reaping the node it's running on mid-harvest would kill the run and
+// strand whatever node it was in the middle of draining, so the node
+// running grim-reaper is placed last in the strategy's ordering.
+// splitForced and preferFree run on that ordering afterwards, so a
+// forced-reap match or an already-free self node can still be selected
+// ahead of other nodes.
+func deprioritizeSelf(ordered []corev1.Node, selfNodeName string) []corev1.Node {
+	if selfNodeName == "" {
+		return ordered
+	}
+
+	selfIndex := -1
+	for i := range ordered {
+		if ordered[i].Name == selfNodeName {
+			selfIndex = i
+			break
+		}
+	}
+	if selfIndex == -1 {
+		return ordered
+	}
+
+	self := ordered[selfIndex]
+	ordered = append(ordered[:selfIndex], ordered[selfIndex+1:]...)
+	return append(ordered, self)
+}
+
+// isDaemonSetPod reports whether pod is owned by a DaemonSet. A DaemonSet's
+// controller reschedules an evicted pod right back onto the same node, so
+// such pods don't count as work when judging whether a node is effectively
+// empty.
+func isDaemonSetPod(pod corev1.Pod) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+// isFree reports whether harvesting node would cost nothing extra: it's
+// already unschedulable, or every pod still on it is a DaemonSet pod that
+// will simply be rescheduled in place. A ListPodsOnNode error is treated as
+// "not free" -- the conservative answer is to leave the node's priority
+// unchanged.
+func (g *GrimReaper) isFree(ctx context.Context, node corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return true
+	}
+
+	pods, err := g.Pods.ListPodsOnNode(ctx, node.Name)
+	if err != nil {
+		return false
+	}
+	for i := range pods {
+		if !isDaemonSetPod(pods[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// preferFree stable-partitions ordered so already-free nodes (per isFree)
+// come before the rest, since harvesting them costs nothing: no busy pods
+// need to move and, if already cordoned, no scheduler state needs to
+// change. Relative order within each partition is left as the selection
+// strategy produced it.
+func (g *GrimReaper) preferFree(ctx context.Context, ordered []corev1.Node) []corev1.Node {
+	var free, busy []corev1.Node
+	for i := range ordered {
+		if g.isFree(ctx, ordered[i]) {
+			free = append(free, ordered[i])
+		} else {
+			busy = append(busy, ordered[i])
+		}
+	}
+	return append(free, busy...)
+}
+
+// MarkNodesForDestruction cordons each node so the scheduler stops placing
+// new pods on it ahead of harvesting.
+func (g *GrimReaper) MarkNodesForDestruction(ctx context.Context, nodes []corev1.Node) error {
+	runID := newRunID()
+	for i := range nodes {
+		if g.Config.DryRun {
+			log.Printf("dry-run: would cordon node %s", nodes[i].Name)
+			continue
+		}
+		if err := g.Nodes.Cordon(ctx, &nodes[i]); err != nil {
+			return fmt.Errorf("cordoning node %s: %w", nodes[i].Name, err)
+		}
+		g.recordEvent(&nodes[i], corev1.EventTypeNormal, "Cordoned", "grim-reaper cordoned this node ahead of reaping (reason: %s)", reapReason(&nodes[i]))
+		g.persistReapReason(ctx, &nodes[i])
+		g.applyReapTaint(ctx, &nodes[i], runID)
+		g.setReapingCondition(ctx, &nodes[i], runID)
+		g.preAnnounce(ctx, &nodes[i])
+	}
+	return nil
+}
+
+// newRunID returns an identifier correlating every node a single
+// MarkNodesForDestruction call touches, so an operator reading a taint or
+// condition on one node can find the others it was reaped alongside.
+func newRunID() string {
+	return fmt.Sprintf("run-%d", time.Now().UnixNano())
+}
+
+// MarkedAtAnnotation records, as an RFC 3339 timestamp, when a node was
+// cordoned by MarkNodesForDestruction. markerIsStale uses it to tell an
+// abandoned marker from one a slow or resuming harvest is still working
+// through.
+const MarkedAtAnnotation = "grim-reaper.io/marked-at"
+
+// MarkedByAnnotation records the identity (os.Hostname(), the pod name
+// under the standard Deployment manifest) of the grim-reaper instance that
+// marked a node for destruction, so an operator can tell which replica is
+// responsible when more than one is running.
+const MarkedByAnnotation = "grim-reaper.io/marked-by"
+
+// markedByIdentity returns this process's hostname, or "unknown" if it
+// can't be determined.
+func markedByIdentity() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return host
+}
+
+// persistReapReason writes the ReapReason selection recorded in memory, the
+// current time, and this instance's identity onto the node object itself,
+// so all three are still there for Harvest (metrics, notifications) and
+// gcStaleMarkers to read back even across a restart. A no-op if selection
+// never tagged the node.
+func (g *GrimReaper) persistReapReason(ctx context.Context, node *corev1.Node) {
+	reason, ok := node.Annotations[ReapReasonAnnotation]
+	if !ok {
+		return
+	}
+	annotations := map[string]interface{}{
+		ReapReasonAnnotation: reason,
+		MarkedAtAnnotation:   time.Now().UTC().Format(time.RFC3339),
+		MarkedByAnnotation:   markedByIdentity(),
+	}
+	if err := g.Nodes.Annotate(ctx, node, annotations); err != nil {
+		log.Printf("recording reap reason on node %s: %v", node.Name, err)
+	}
+}
+
+// runIDPlaceholder, when present in Config.ReapTaint's value, is replaced
+// with the current run's ID before the taint is applied, so the taint
+// itself carries the correlation ID the request that motivated it asked
+// for (grim-reaper.io/reaping=<run-id>:NoSchedule) without hardcoding one
+// specific taint value for every deployment.
+const runIDPlaceholder = "$RUNID"
+
+// applyReapTaint applies Config.ReapTaint (NoSchedule) to node, in addition
+// to cordoning it, so other controllers and humans can see why the node is
+// being removed straight from `kubectl describe node`, and so pods that
+// explicitly tolerate the taint can still be scheduled there -- unlike a
+// cordon, which blocks scheduling unconditionally. A no-op if ReapTaint is
+// unset.
+func (g *GrimReaper) applyReapTaint(ctx context.Context, node *corev1.Node, runID string) {
+	if g.Config.ReapTaint == "" {
+		return
+	}
+	key, value, err := config.ParseReapTaint(g.Config.ReapTaint)
+	if err != nil {
+		log.Printf("applying reap taint to node %s: %v", node.Name, err)
+		return
+	}
+	value = strings.ReplaceAll(value, runIDPlaceholder, runID)
+	if err := g.Nodes.Taint(ctx, node, key, value, corev1.TaintEffectNoSchedule); err != nil {
+		log.Printf("applying reap taint to node %s: %v", node.Name, err)
+	}
+}
+
+// ReapingConditionType is a well-known node condition grim-reaper sets
+// while a node is marked for destruction, so other controllers and
+// dashboards can recognize "this node is being removed by grim-reaper"
+// structurally, rather than inferring it from an anonymous unschedulable
+// flag indistinguishable from a manual cordon.
+const ReapingConditionType corev1.NodeConditionType = "grim-reaper.io/Reaping"
+
+// setReapingCondition sets ReapingConditionType True on node, with runID
+// and its reap reason in the message, so the condition alone identifies
+// both why and under which run the node was marked for destruction.
+func (g *GrimReaper) setReapingCondition(ctx context.Context, node *corev1.Node, runID string) {
+	condition := corev1.NodeCondition{
+		Type:               ReapingConditionType,
+		Status:             corev1.ConditionTrue,
+		Reason:             "MarkedForDestruction",
+		Message:            fmt.Sprintf("grim-reaper marked this node for destruction (run %s, reason: %s)", runID, reapReason(node)),
+		LastTransitionTime: metav1.Now(),
+	}
+	if err := g.Nodes.SetCondition(ctx, node, condition); err != nil {
+		log.Printf("setting reaping condition on node %s: %v", node.Name, err)
+	}
+}
+
+// preAnnounce notifies the namespaces hosted on node and then waits
+// PreAnnounceDuration before returning, giving applications with
+// pre-stop coordination a chance to prepare before draining actually
+// starts. It is a no-op when PreAnnounceDuration is zero.
+func (g *GrimReaper) preAnnounce(ctx context.Context, node *corev1.Node) {
+	if g.Config.PreAnnounceDuration <= 0 {
+		return
+	}
+
+	pods, err := g.Pods.ListPodsOnNode(ctx, node.Name)
+	if err != nil {
+		log.Printf("pre-announce: listing pods on node %s: %v", node.Name, err)
+	}
+
+	namespaces := map[string]bool{}
+	for _, pod := range pods {
+		namespaces[pod.Namespace] = true
+	}
+
+	if g.Notifier != nil {
+		message := fmt.Sprintf("grim-reaper will begin draining node %s in %s", node.Name, g.Config.PreAnnounceDuration)
+		for ns := range namespaces {
+			if err := g.Notifier.NotifyEviction(ns, message); err != nil {
+				log.Printf("pre-announce: notifying namespace %s: %v", ns, err)
+			}
+		}
+	}
+
+	log.Printf("pre-announce: waiting %s before draining node %s", g.Config.PreAnnounceDuration, node.Name)
+	time.Sleep(g.Config.PreAnnounceDuration)
+}
+
+// Harvest drains and deletes a single node: evict every pod, then remove
+// the Node object once it's empty.
+func (g *GrimReaper) Harvest(ctx context.Context, node *corev1.Node) error {
+	if g.Config.DryRun {
+		log.Printf("dry-run: would harvest node %s", node.Name)
+		return nil
+	}
+
+	if failures := harvestFailureCount(node); g.Config.MaxHarvestFailures > 0 && failures >= g.Config.MaxHarvestFailures {
+		g.notifyHarvestAbandoned(node, failures)
+		return fmt.Errorf("node %s has failed harvesting %d time(s) (last error: %s): %w",
+			node.Name, failures, node.Annotations[HarvestLastErrorAnnotation], errHarvestAbandoned)
+	}
+
+	transcript := NewTranscript(node.Name)
+	if err := g.harvest(ctx, node, transcript); err != nil {
+		g.recordHarvestFailure(ctx, node, err)
+		g.notifyHarvestFailure(node, err, transcript)
+		return err
+	}
+	g.clearHarvestFailures(ctx, node)
+	metrics.NodesReapedTotal.WithLabelValues(reapReason(node)).Inc()
+	return nil
+}
+
+// harvestFailureCount returns how many consecutive harvest attempts against
+// node have failed, per HarvestFailureCountAnnotation. An unparseable or
+// missing annotation counts as zero.
+func harvestFailureCount(node *corev1.Node) int {
+	raw, ok := node.Annotations[HarvestFailureCountAnnotation]
+	if !ok {
+		return 0
+	}
+	count, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+// recordHarvestFailure annotates node with its incremented failure count and
+// err's message, so the next run (even after a restart) knows this node has
+// been misbehaving.
+func (g *GrimReaper) recordHarvestFailure(ctx context.Context, node *corev1.Node, err error) {
+	count := harvestFailureCount(node) + 1
+	annotations := map[string]interface{}{
+		HarvestFailureCountAnnotation: strconv.Itoa(count),
+		HarvestLastErrorAnnotation:    err.Error(),
+	}
+	if annotateErr := g.Nodes.Annotate(ctx, node, annotations); annotateErr != nil {
+		log.Printf("recording harvest failure on node %s: %v", node.Name, annotateErr)
+	}
+}
+
+// clearHarvestFailures removes any harvest-failure bookkeeping left on node
+// by a prior attempt, now that it's been harvested successfully.
+func (g *GrimReaper) clearHarvestFailures(ctx context.Context, node *corev1.Node) {
+	if harvestFailureCount(node) == 0 {
+		return
+	}
+	annotations := map[string]interface{}{
+		HarvestFailureCountAnnotation: nil,
+		HarvestLastErrorAnnotation:    nil,
+	}
+	if err := g.Nodes.Annotate(ctx, node, annotations); err != nil {
+		log.Printf("clearing harvest failure bookkeeping on node %s: %v", node.Name, err)
+	}
+}
+
+// notifyHarvestAbandoned tells the platform channel that node has exceeded
+// MaxHarvestFailures and will no longer be retried automatically.
+func (g *GrimReaper) notifyHarvestAbandoned(node *corev1.Node, failures int) {
+	if g.Notifier == nil {
+		return
+	}
+	message := fmt.Sprintf("giving up on node %s (reap reason: %s) after %d failed harvest attempt(s); last error: %s -- this node needs manual attention",
+		node.Name, reapReason(node), failures, node.Annotations[HarvestLastErrorAnnotation])
+	if err := g.Notifier.NotifyEviction("", message); err != nil {
+		log.Printf("notifying about abandoned node %s: %v", node.Name, err)
+	}
+}
+
+func (g *GrimReaper) harvest(ctx context.Context, node *corev1.Node, transcript *Transcript) error {
+	instanceID, err := g.resolveInstanceID(node)
+	if err != nil {
+		return err
+	}
+	if instanceID != "" {
+		transcript.Record("protecting instance %s from cloud scale-in", instanceID)
+		if err := g.Cloud.ProtectFromScaleIn(ctx, instanceID, true); err != nil {
+			return fmt.Errorf("protecting instance %s from scale-in: %w", instanceID, err)
+		}
+	}
+
+	drainer := g.Drainer
+	if drainer == nil {
+		drainer = &customDrainer{pods: g.Pods, barePodPolicy: BarePodPolicy(g.Config.BarePodPolicy), honorSafeToEvict: g.Config.HonorSafeToEvict, namespaces: g.Namespaces, allowedTenantTiers: g.Config.AllowedTenantTiers, maxConcurrentEvictions: g.Config.MaxConcurrentEvictions, maxMemoryMB: g.Config.MaxMemoryMB, deletionTimeout: g.Config.EvictDeletionTimeout, forceDeleteAfterTimeout: g.Config.ForceDeleteAfterTimeout, forceDeleteGracePeriod: g.Config.ForceDeleteGracePeriod, notifier: g.Notifier, rateLimiter: newEvictionRateLimiter(g.Config.EvictionRateLimit)}
+	}
+
+	transcript.Record("starting drain")
+	g.recordEvent(node, corev1.EventTypeNormal, "DrainStarted", "grim-reaper started draining this node")
+	evictedByNamespace, err := drainer.Drain(ctx, node)
+	if err != nil {
+		transcript.Record("drain failed: %v", err)
+		g.recordEvent(node, corev1.EventTypeWarning, "PodEvictionFailed", "draining this node failed: %v", err)
+		return fmt.Errorf("draining node %s: %w", node.Name, err)
+	}
+	transcript.Record("drain complete, evicted %v", evictedByNamespace)
+	g.recordEvent(node, corev1.EventTypeNormal, "DrainCompleted", "grim-reaper finished draining this node, evicted %v", evictedByNamespace)
+	g.notifyEvictions(node, evictedByNamespace)
+
+	if g.Config.SoakDuration > 0 {
+		log.Printf("node %s drained; soaking cordoned for %s before deletion so it can still be rolled back", node.Name, g.Config.SoakDuration)
+		transcript.Record("soaking cordoned for %s before deletion", g.Config.SoakDuration)
+		time.Sleep(g.Config.SoakDuration)
+	}
+
+	transcript.Record("deleting node object")
+	if err := g.Nodes.Delete(ctx, node); err != nil {
+		return err
+	}
+	g.recordEvent(node, corev1.EventTypeNormal, "NodeDeleted", "grim-reaper deleted this node object")
+
+	if instanceID == "" {
+		return nil
+	}
+
+	deletionMode := cloud.DeletionMode(g.Config.InstanceDeletionMode)
+
+	if g.AsyncTerminator != nil {
+		transcript.Record("handing instance %s off to the async terminator", instanceID)
+		g.AsyncTerminator.Enqueue(node.Name, instanceID, deletionMode)
+		return nil
+	}
+
+	transcript.Record("terminating instance %s", instanceID)
+	if err := g.Cloud.TerminateInstance(ctx, instanceID, deletionMode); err != nil {
+		return fmt.Errorf("terminating instance %s: %w", instanceID, err)
+	}
+	transcript.Record("waiting for instance %s termination to be confirmed", instanceID)
+	return g.Cloud.WaitForTermination(ctx, instanceID, g.CloudTerminationTimeout)
+}
+
+// notifyHarvestFailure notifies the platform channel that harvesting node
+// failed, attaching the harvest transcript so far unless
+// AttachHarvestTranscripts is disabled.
+func (g *GrimReaper) notifyHarvestFailure(node *corev1.Node, err error, transcript *Transcript) {
+	if g.Notifier == nil {
+		return
+	}
+
+	message := fmt.Sprintf("harvesting node %s (reap reason: %s) failed: %v", node.Name, reapReason(node), err)
+	if g.Config.AttachHarvestTranscripts {
+		message = message + "\n\n" + transcript.String()
+	}
+	if notifyErr := g.Notifier.NotifyEviction("", message); notifyErr != nil {
+		log.Printf("notifying about harvest failure for node %s: %v", node.Name, notifyErr)
+	}
+}
+
+// resolveInstanceID returns the cloud instance ID backing node, or "" if no
+// ProviderIDToInstanceID function is configured (e.g. cloud.NoopProvider).
+func (g *GrimReaper) resolveInstanceID(node *corev1.Node) (string, error) {
+	if g.ProviderIDToInstanceID == nil {
+		return "", nil
+	}
+	instanceID, err := g.ProviderIDToInstanceID(node.Spec.ProviderID)
+	if err != nil {
+		return "", fmt.Errorf("resolving instance ID for node %s: %w", node.Name, err)
+	}
+	return instanceID, nil
+}
+
+// notifyEvictions tells the platform channel, and each affected namespace's
+// owning team, how many of their pods were evicted from node.
+func (g *GrimReaper) notifyEvictions(node *corev1.Node, evictedByNamespace map[string]int) {
+	if g.Notifier == nil {
+		return
+	}
+	for ns, count := range evictedByNamespace {
+		message := fmt.Sprintf("grim-reaper evicted %d pod(s) in namespace %s from node %s (reap reason: %s)", count, ns, node.Name, reapReason(node))
+		if err := g.Notifier.NotifyEviction(ns, message); err != nil {
+			log.Printf("notifying eviction for namespace %s: %v", ns, err)
+		}
+	}
+}
+
+// Run executes one full reap cycle: select nodes, mark them, then harvest
+// them, up to Config.MaxConcurrentNodeDrains at once. If Config.BatchSize is
+// set, dispatch pauses every BatchSize nodes until that wave's harvests have
+// all finished, then sleeps Config.BatchPause before starting the next
+// wave. A node's harvest failing or failing post-harvest verification stops
+// any further nodes from being dispatched, but lets nodes already in
+// flight finish; Run returns the first such error once every in-flight
+// harvest has completed.
+func (g *GrimReaper) Run(ctx context.Context) error {
+	report := &RunReport{StartedAt: time.Now()}
+	g.LastReport = report
+	var reportMu sync.Mutex
+	g.PassoverObserver = func(nodeName, reason string) {
+		reportMu.Lock()
+		report.PassedOver = append(report.PassedOver, NodePassoverReport{Name: nodeName, Reason: reason})
+		reportMu.Unlock()
+	}
+	defer func() {
+		g.PassoverObserver = nil
+		report.FinishedAt = time.Now()
+	}()
+
+	nodes, err := g.GetNodesToReap(ctx)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return err
+	}
+	if len(nodes) == 0 {
+		log.Println("no nodes eligible for reaping this run")
+		return nil
+	}
+
+	if err := g.checkUnschedulablePodsCircuitBreaker(ctx, nil); err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return err
+	}
+
+	if g.Config.DryRun {
+		if forecast, err := ForecastDisruption(ctx, nodes, g.Pods); err != nil {
+			log.Printf("forecasting per-workload disruption: %v", err)
+		} else {
+			LogForecast(forecast)
+		}
+	}
+
+	if err := g.MarkNodesForDestruction(ctx, nodes); err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return err
+	}
+
+	if g.Config.Canary && len(nodes) > 1 {
+		log.Printf("canary: harvesting node %s alone before the rest of the %d-node batch", nodes[0].Name, len(nodes))
+		canaryResult, err := g.runCanary(ctx, &nodes[0])
+		report.Reaped = append(report.Reaped, canaryResult)
+		if err != nil {
+			log.Printf("canary: %v; not proceeding with the rest of the batch", err)
+			report.Errors = append(report.Errors, err.Error())
+			return err
+		}
+		log.Printf("canary: node %s absorbed cleanly, proceeding with the rest of the batch", nodes[0].Name)
+		nodes = nodes[1:]
+	}
+
+	maxStatefulBytes := g.maxStatefulBytesPerRun()
+	var statefulBytesDisplaced int64
+
+	concurrency := g.Config.MaxConcurrentNodeDrains
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	var firstErr error
+	stopDispatch := false
+
+	for i := range nodes {
+		reportMu.Lock()
+		stop := stopDispatch
+		reportMu.Unlock()
+		if stop {
+			break
+		}
+		if g.StopRequested != nil && g.StopRequested() {
+			log.Printf("shutdown requested; stopping before dispatching node %s (%d/%d dispatched)", nodes[i].Name, i, len(nodes))
+			reportMu.Lock()
+			report.Errors = append(report.Errors, ErrShutdownRequested.Error())
+			if firstErr == nil {
+				firstErr = ErrShutdownRequested
+			}
+			reportMu.Unlock()
+			break
+		}
+
+		if g.Config.BatchSize > 0 && i > 0 && i%g.Config.BatchSize == 0 {
+			wg.Wait()
+			log.Printf("batch: wave of %d node(s) finished, pausing %s before dispatching the next wave (%d/%d nodes dispatched so far)", g.Config.BatchSize, g.Config.BatchPause, i, len(nodes))
+			ctxDone := false
+			select {
+			case <-time.After(g.Config.BatchPause):
+			case <-ctx.Done():
+				ctxDone = true
+			}
+			if ctxDone {
+				reportMu.Lock()
+				report.Errors = append(report.Errors, ctx.Err().Error())
+				if firstErr == nil {
+					firstErr = ctx.Err()
+				}
+				reportMu.Unlock()
+				break
+			}
+		}
+
+		if i > 0 {
+			g.pace(ctx, len(nodes))
+		}
+		g.awaitSchedulerCapacity(ctx, &nodes[i])
+		if err := g.checkUnschedulablePodsCircuitBreaker(ctx, &nodes[i]); err != nil {
+			log.Printf("unschedulable-pods circuit breaker tripped before dispatching node %s: %v", nodes[i].Name, err)
+			reportMu.Lock()
+			report.Errors = append(report.Errors, err.Error())
+			if firstErr == nil {
+				firstErr = err
+			}
+			reportMu.Unlock()
+			break
+		}
+		if err := g.checkReadyNodeFloor(ctx, &nodes[i]); err != nil {
+			log.Printf("ready-node floor check failed before dispatching node %s: %v", nodes[i].Name, err)
+			reportMu.Lock()
+			report.Errors = append(report.Errors, err.Error())
+			if firstErr == nil {
+				firstErr = err
+			}
+			reportMu.Unlock()
+			break
+		}
+
+		evictedPods := 0
+		var statefulBytes int64
+		pods, err := g.Pods.ListPodsOnNode(ctx, nodes[i].Name)
+		if err != nil {
+			log.Printf("counting pods on node %s for the run report: %v", nodes[i].Name, err)
+		} else {
+			evictedPods = len(pods)
+			statefulBytes = g.nodeStatefulBytes(ctx, pods)
+		}
+
+		if maxStatefulBytes > 0 && statefulBytesDisplaced+statefulBytes > maxStatefulBytes {
+			log.Printf("node %s carries ~%d byte(s) of stateful data, which would exceed --max-stateful-gb-per-run; leaving it marked for the next run", nodes[i].Name, statefulBytes)
+			g.recordPassovers(nodes[i:i+1], PassoverReasonStatefulBudget)
+			break
+		}
+		statefulBytesDisplaced += statefulBytes
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(node *corev1.Node, pods []corev1.Pod, evictedPods int, statefulBytes int64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, harvestErr := g.runOneNode(ctx, node, pods, evictedPods, statefulBytes)
+
+			reportMu.Lock()
+			report.Reaped = append(report.Reaped, result)
+			if harvestErr != nil {
+				report.Errors = append(report.Errors, harvestErr.Error())
+				if firstErr == nil {
+					firstErr = harvestErr
+					stopDispatch = true
+				}
+			} else {
+				log.Printf("reaped node %s", node.Name)
+			}
+			reportMu.Unlock()
+		}(&nodes[i], pods, evictedPods, statefulBytes)
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+// runOneNode harvests a single node and, on success, verifies the
+// post-harvest invariants, returning the outcome as a NodeReapReport
+// alongside the error (if any) that should stop the run. Safe to call
+// concurrently for distinct nodes, up to Config.MaxConcurrentNodeDrains at
+// once; see Run.
+func (g *GrimReaper) runOneNode(ctx context.Context, node *corev1.Node, pods []corev1.Pod, evictedPods int, statefulBytes int64) (NodeReapReport, error) {
+	nodeStart := time.Now()
+
+	var preFiringAlerts map[string]bool
+	if g.Config.PostHarvestVerification && g.Alerts != nil {
+		if firing, err := g.Alerts.FiringAlerts(ctx); err != nil {
+			log.Printf("snapshotting firing alerts before harvesting node %s: %v", node.Name, err)
+		} else {
+			preFiringAlerts = make(map[string]bool, len(firing))
+			for _, name := range firing {
+				preFiringAlerts[name] = true
+			}
+		}
+	}
+
+	g.markHarvesting(node.Name)
+	harvestErr := g.Harvest(ctx, node)
+	g.unmarkHarvesting(node.Name)
+
+	result := NodeReapReport{
+		Name:            node.Name,
+		Reason:          reapReason(node),
+		DurationSeconds: time.Since(nodeStart).Seconds(),
+		EvictedPods:     evictedPods,
+		StatefulBytes:   statefulBytes,
+	}
+	if harvestErr != nil {
+		result.Error = harvestErr.Error()
+	}
+	if g.Config.CollectEventCorrelation {
+		result.CorrelatedEvents = g.correlateEvents(ctx, node, pods, nodeStart)
+	}
+	if harvestErr == nil {
+		result.Verification = g.verifyPostHarvest(ctx, node, pods, preFiringAlerts)
+	}
+	if delay := g.statefulReplicationDelay(statefulBytes); delay > 0 {
+		log.Printf("node %s carried ~%d byte(s) of stateful data; pausing %s for re-replication/attachment before the next harvest", node.Name, statefulBytes, delay)
+		time.Sleep(delay)
+	}
+
+	if harvestErr != nil {
+		return result, fmt.Errorf("harvesting node %s: %w", node.Name, harvestErr)
+	}
+	if err := g.awaitReplacementsReady(ctx, node, pods); err != nil {
+		return result, err
+	}
+	g.awaitNodeCooldown(ctx, node, pods)
+	if result.Verification != nil && !result.Verification.OK {
+		return result, fmt.Errorf("post-harvest verification failed for node %s: %s", node.Name, strings.Join(result.Verification.Failures, "; "))
+	}
+	return result, nil
+}
+
+// pace sleeps between harvests, scaling with the cluster's size and how
+// much the scheduler is currently struggling to place Pending pods.
+func (g *GrimReaper) pace(ctx context.Context, clusterSize int) {
+	if g.Config.HarvestPacingBase <= 0 {
+		return
+	}
+
+	var ages []time.Duration
+	if pending, err := g.Pods.ListPendingPods(ctx); err != nil {
+		log.Printf("pacing: listing pending pods: %v", err)
+	} else {
+		ages = pendingPodAges(pending, time.Now())
+	}
+
+	delay := pacingDelay(g.Config.HarvestPacingBase, clusterSize, ages)
+	log.Printf("pacing: waiting %s before harvesting the next node", delay)
+	time.Sleep(delay)
+}
@@ -0,0 +1,116 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// awaitReplacementsReady waits, after a harvest, until every pod evicted
+// from node has a Ready replacement from the same controller, up to
+// Config.ReplacementReadyTimeout. A zero timeout skips the wait entirely.
+// If the timeout elapses first, the harvest counts toward
+// Config.MaxConsecutiveReplacementFailures; once that many harvests in a
+// row have timed out, awaitReplacementsReady returns an error that stops
+// the run, on the theory that a scheduler that can't place replacements
+// for several nodes running is unlikely to place them for the next one
+// either.
+func (g *GrimReaper) awaitReplacementsReady(ctx context.Context, node *corev1.Node, evictedPods []corev1.Pod) error {
+	if g.Config.ReplacementReadyTimeout <= 0 {
+		return nil
+	}
+
+	owners := map[string]bool{}
+	for i := range evictedPods {
+		if key := podOwnerKey(&evictedPods[i]); key != "" {
+			owners[key] = true
+		}
+	}
+	if len(owners) == 0 {
+		return nil
+	}
+
+	interval := g.Config.BackpressureCheckInterval
+	if interval <= 0 {
+		interval = DefaultBackpressureCheckInterval
+	}
+
+	deadline := time.Now().Add(g.Config.ReplacementReadyTimeout)
+	for {
+		pods, err := g.Pods.ListPods(ctx)
+		if err != nil {
+			log.Printf("node %s: waiting for replacement pods: listing pods: %v", node.Name, err)
+		} else if replacementsReady(pods, owners) {
+			g.resetReplacementFailures()
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return g.recordReplacementFailure(node)
+		}
+
+		log.Printf("node %s: waiting for replacement pods to become Ready", node.Name)
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// replacementsReady reports whether every owner key has at least one Ready
+// pod among pods.
+func replacementsReady(pods []corev1.Pod, owners map[string]bool) bool {
+	ready := map[string]bool{}
+	for i := range pods {
+		key := podOwnerKey(&pods[i])
+		if key == "" || !owners[key] || !podReady(&pods[i]) {
+			continue
+		}
+		ready[key] = true
+	}
+	return len(ready) == len(owners)
+}
+
+// podReady reports whether pod's Ready condition is True.
+func podReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// recordReplacementFailure bumps the consecutive-timeout counter and, once
+// it reaches Config.MaxConsecutiveReplacementFailures, returns an error
+// aborting the run. A zero MaxConsecutiveReplacementFailures just logs and
+// lets the run continue.
+func (g *GrimReaper) recordReplacementFailure(node *corev1.Node) error {
+	log.Printf("node %s: replacement pods did not become Ready within %s", node.Name, g.Config.ReplacementReadyTimeout)
+
+	if g.Config.MaxConsecutiveReplacementFailures <= 0 {
+		return nil
+	}
+
+	g.replacementFailuresMu.Lock()
+	g.consecutiveReplacementFailures++
+	count := g.consecutiveReplacementFailures
+	g.replacementFailuresMu.Unlock()
+
+	if count >= g.Config.MaxConsecutiveReplacementFailures {
+		return fmt.Errorf("%d consecutive node(s) had replacement pods fail to become Ready within %s", count, g.Config.ReplacementReadyTimeout)
+	}
+	return nil
+}
+
+// resetReplacementFailures clears the consecutive-timeout counter after a
+// harvest whose replacements did become Ready in time.
+func (g *GrimReaper) resetReplacementFailures() {
+	g.replacementFailuresMu.Lock()
+	g.consecutiveReplacementFailures = 0
+	g.replacementFailuresMu.Unlock()
+}
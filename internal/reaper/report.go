@@ -0,0 +1,94 @@
+package reaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// NodeReapReport describes the outcome of reaping a single node during a
+// run, as recorded in RunReport.Reaped.
+type NodeReapReport struct {
+	Name            string  `json:"name"`
+	Reason          string  `json:"reason"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	EvictedPods     int     `json:"evictedPods"`
+
+	// StatefulBytes is the total provisioned storage of every PVC-backed
+	// volume among the node's pods, as estimated by PVCs. Zero if PVCs
+	// is unset or none of the node's pods use a PVC.
+	StatefulBytes int64 `json:"statefulBytes,omitempty"`
+
+	// Error is the harvest error's message, or empty if the node was
+	// reaped successfully.
+	Error string `json:"error,omitempty"`
+
+	// CorrelatedEvents summarizes the Kubernetes Events involving this
+	// node and its evicted pods during the harvest, grouped by Reason, so
+	// a responder doing post-reap impact analysis (e.g. "did this node's
+	// pods get stuck FailedScheduling elsewhere?") doesn't have to go dig
+	// through `kubectl get events` themselves. Populated only when
+	// config.Config.CollectEventCorrelation is set and GrimReaper.Events
+	// is wired up.
+	CorrelatedEvents []CorrelatedEvent `json:"correlatedEvents,omitempty"`
+
+	// Verification records the outcome of the post-harvest invariant
+	// checks run against this node (see config.Config.PostHarvestVerification).
+	// Nil if verification is disabled.
+	Verification *VerificationResult `json:"verification,omitempty"`
+}
+
+// VerificationResult is the outcome of the post-harvest invariant checks
+// run against a single node, as recorded in NodeReapReport.Verification.
+type VerificationResult struct {
+	OK       bool     `json:"ok"`
+	Failures []string `json:"failures,omitempty"`
+}
+
+// CorrelatedEvent is one Reason's worth of Kubernetes Events collected for
+// a single harvested node, as recorded in NodeReapReport.CorrelatedEvents.
+type CorrelatedEvent struct {
+	Reason      string `json:"reason"`
+	Count       int32  `json:"count"`
+	LastMessage string `json:"lastMessage"`
+}
+
+// NodePassoverReport records that a node was skipped during selection, and
+// why, as recorded in RunReport.PassedOver.
+type NodePassoverReport struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// RunReport is the structured, machine-readable outcome of a single Run
+// call: which nodes were reaped and how long each took, which were passed
+// over and why, and any errors encountered along the way. See WriteReport.
+type RunReport struct {
+	StartedAt  time.Time            `json:"startedAt"`
+	FinishedAt time.Time            `json:"finishedAt"`
+	Reaped     []NodeReapReport     `json:"reaped"`
+	PassedOver []NodePassoverReport `json:"passedOver"`
+	Errors     []string             `json:"errors"`
+}
+
+// WriteReport writes report as JSON to path, or to stdout if path is "-".
+// It's a no-op if path is empty, for callers that treat --report-file as
+// opt-in.
+func WriteReport(report *RunReport, path string) error {
+	if path == "" || report == nil {
+		return nil
+	}
+
+	encoded, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding run report: %w", err)
+	}
+	encoded = append(encoded, '\n')
+
+	if path == "-" {
+		_, err := os.Stdout.Write(encoded)
+		return err
+	}
+	return os.WriteFile(path, encoded, 0644)
+}
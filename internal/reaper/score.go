@@ -0,0 +1,90 @@
+package reaper
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ScoreAnnotation lets a node (or whatever labels it, e.g. a node pool
+// controller) contribute an extra signal to the weighted score, for
+// criteria grim-reaper has no other way to know about.
+const ScoreAnnotation = "grim-reaper.io/score"
+
+// ScoreWeights weights each signal going into a node's weighted reap
+// score. Higher-scoring nodes are reaped first.
+type ScoreWeights struct {
+	// Age weights the node's age in hours.
+	Age float64
+	// PodCount weights the number of pods currently scheduled on the node.
+	PodCount float64
+	// Restarts weights the total container restart count across the
+	// node's pods.
+	Restarts float64
+	// Cordoned weights whether the node is already cordoned (1) or not (0),
+	// so partially-completed work from a previous run is finished first.
+	Cordoned float64
+	// Annotation weights the float value of ScoreAnnotation, if present.
+	Annotation float64
+}
+
+// NodeScore computes node's weighted reap score: higher means a better
+// candidate for reaping.
+func NodeScore(ctx context.Context, node corev1.Node, pods PodEvictor, weights ScoreWeights) float64 {
+	ageHours := time.Since(node.CreationTimestamp.Time).Hours()
+	podCount := float64(len(podsOnNode(ctx, node.Name, pods)))
+	restarts := float64(totalRestarts(ctx, node.Name, pods))
+	var cordoned float64
+	if node.Spec.Unschedulable {
+		cordoned = 1
+	}
+
+	var annotationScore float64
+	if raw, ok := node.Annotations[ScoreAnnotation]; ok {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			log.Printf("node %s has invalid %s annotation %q, ignoring: %v", node.Name, ScoreAnnotation, raw, err)
+		} else {
+			annotationScore = parsed
+		}
+	}
+
+	return weights.Age*ageHours +
+		weights.PodCount*podCount +
+		weights.Restarts*restarts +
+		weights.Cordoned*cordoned +
+		weights.Annotation*annotationScore
+}
+
+func podsOnNode(ctx context.Context, nodeName string, pods PodEvictor) []corev1.Pod {
+	podList, err := pods.ListPodsOnNode(ctx, nodeName)
+	if err != nil {
+		return nil
+	}
+	return podList
+}
+
+// weightedSelector orders nodes by NodeScore, highest first, logging each
+// node's score so the contribution of each signal is visible in debug logs
+// and dry-run output.
+type weightedSelector struct {
+	weights ScoreWeights
+}
+
+func (s weightedSelector) Order(ctx context.Context, nodes []corev1.Node, pods PodEvictor) []corev1.Node {
+	ordered := append([]corev1.Node{}, nodes...)
+	scores := make(map[string]float64, len(ordered))
+	for i := range ordered {
+		score := NodeScore(ctx, ordered[i], pods, s.weights)
+		scores[ordered[i].Name] = score
+		log.Printf("node %s weighted reap score: %.3f", ordered[i].Name, score)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return scores[ordered[i].Name] > scores[ordered[j].Name]
+	})
+	return ordered
+}
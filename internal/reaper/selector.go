@@ -0,0 +1,163 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Selector orders candidate nodes most-reapable first. Implementations may
+// use pods to inspect what's actually running on each node (e.g. restart
+// counts); pods is never nil when called from GetNodesToReap.
+type Selector interface {
+	Order(ctx context.Context, nodes []corev1.Node, pods PodEvictor) []corev1.Node
+}
+
+// NewSelector returns the built-in Selector for name. Supported values:
+// "oldest" (default), "newest", "random", "emptiest", "fragmentation",
+// "most-restarts".
+func NewSelector(name string) (Selector, error) {
+	switch name {
+	case "", "oldest":
+		return oldestFirstSelector{}, nil
+	case "newest":
+		return newestFirstSelector{}, nil
+	case "random":
+		return randomSelector{}, nil
+	case "emptiest":
+		return emptiestFirstSelector{}, nil
+	case "fragmentation":
+		return fragmentationSelector{}, nil
+	case "most-restarts":
+		return mostRestartsSelector{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported selection strategy %q", name)
+	}
+}
+
+// oldestFirstSelector reaps the longest-lived nodes first, the default:
+// it favors cycling the whole fleet through steadily over time.
+type oldestFirstSelector struct{}
+
+func (oldestFirstSelector) Order(ctx context.Context, nodes []corev1.Node, pods PodEvictor) []corev1.Node {
+	ordered := append([]corev1.Node{}, nodes...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].CreationTimestamp.Before(&ordered[j].CreationTimestamp)
+	})
+	return ordered
+}
+
+// newestFirstSelector reaps the most recently created nodes first, useful
+// for clearing out a bad rollout of fresh capacity before it gets load.
+type newestFirstSelector struct{}
+
+func (newestFirstSelector) Order(ctx context.Context, nodes []corev1.Node, pods PodEvictor) []corev1.Node {
+	ordered := append([]corev1.Node{}, nodes...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[j].CreationTimestamp.Before(&ordered[i].CreationTimestamp)
+	})
+	return ordered
+}
+
+// randomSelector reaps nodes in a random order, spreading disruption evenly
+// across the fleet over many runs instead of favoring any one signal.
+type randomSelector struct{}
+
+func (randomSelector) Order(ctx context.Context, nodes []corev1.Node, pods PodEvictor) []corev1.Node {
+	ordered := append([]corev1.Node{}, nodes...)
+	rand.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+	return ordered
+}
+
+// emptiestFirstSelector reaps nodes with the most spare (unrequested)
+// capacity first, minimizing how many pods need to move per node reaped.
+type emptiestFirstSelector struct{}
+
+func (emptiestFirstSelector) Order(ctx context.Context, nodes []corev1.Node, pods PodEvictor) []corev1.Node {
+	ordered := append([]corev1.Node{}, nodes...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return requestedFraction(ctx, ordered[i], pods) < requestedFraction(ctx, ordered[j], pods)
+	})
+	return ordered
+}
+
+// nodeRequestedFractions returns the fraction of node's allocatable CPU and
+// memory actually requested by the pods running on it, determined from
+// those pods' own requests rather than node.Status.Capacity minus
+// Allocatable -- that gap is just fixed system/kube-reserved overhead and
+// barely varies between same-typed nodes, so it says nothing about how full
+// a node actually is. Returns (0, 0) if allocatable is zero or the pod
+// listing fails.
+func nodeRequestedFractions(ctx context.Context, node corev1.Node, pods PodEvictor) (cpuFraction, memFraction float64) {
+	cpuAllocatable := node.Status.Allocatable.Cpu().MilliValue()
+	memAllocatable := node.Status.Allocatable.Memory().Value()
+	if cpuAllocatable == 0 || memAllocatable == 0 {
+		return 0, 0
+	}
+
+	podList, err := pods.ListPodsOnNode(ctx, node.Name)
+	if err != nil {
+		return 0, 0
+	}
+	cpuRequested, memRequested := podRequests(podList)
+
+	return float64(cpuRequested) / float64(cpuAllocatable), float64(memRequested) / float64(memAllocatable)
+}
+
+// requestedFraction is the larger of a node's requested-CPU and
+// requested-memory fractions, used as a rough "how full is this node"
+// signal.
+func requestedFraction(ctx context.Context, node corev1.Node, pods PodEvictor) float64 {
+	cpuFraction, memFraction := nodeRequestedFractions(ctx, node, pods)
+	if cpuFraction > memFraction {
+		return cpuFraction
+	}
+	return memFraction
+}
+
+// fragmentationSelector reaps nodes with the most stranded resources first.
+// See fragmentationScore for the heuristic.
+type fragmentationSelector struct{}
+
+func (fragmentationSelector) Order(ctx context.Context, nodes []corev1.Node, pods PodEvictor) []corev1.Node {
+	ordered := append([]corev1.Node{}, nodes...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return fragmentationScore(ctx, ordered[i], pods) > fragmentationScore(ctx, ordered[j], pods)
+	})
+	return ordered
+}
+
+// mostRestartsSelector reaps nodes whose pods have restarted the most
+// first, a proxy for flaky underlying hardware or kubelet health.
+type mostRestartsSelector struct{}
+
+func (mostRestartsSelector) Order(ctx context.Context, nodes []corev1.Node, pods PodEvictor) []corev1.Node {
+	ordered := append([]corev1.Node{}, nodes...)
+	restarts := make(map[string]int32, len(ordered))
+	for i := range ordered {
+		restarts[ordered[i].Name] = totalRestarts(ctx, ordered[i].Name, pods)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return restarts[ordered[i].Name] > restarts[ordered[j].Name]
+	})
+	return ordered
+}
+
+func totalRestarts(ctx context.Context, nodeName string, pods PodEvictor) int32 {
+	podList, err := pods.ListPodsOnNode(ctx, nodeName)
+	if err != nil {
+		return 0
+	}
+	var total int32
+	for _, pod := range podList {
+		for _, status := range pod.Status.ContainerStatuses {
+			total += status.RestartCount
+		}
+	}
+	return total
+}
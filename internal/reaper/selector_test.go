@@ -0,0 +1,128 @@
+package reaper
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// stubPodEvictor is a minimal PodEvictor whose ListPodsOnNode answers from a
+// fixed, per-node map; every other method is unused by the selectors under
+// test here.
+type stubPodEvictor struct {
+	podsByNode map[string][]corev1.Pod
+}
+
+func (s stubPodEvictor) ListPodsOnNode(ctx context.Context, nodeName string) ([]corev1.Pod, error) {
+	return s.podsByNode[nodeName], nil
+}
+func (stubPodEvictor) Evict(ctx context.Context, pod *corev1.Pod) error               { return nil }
+func (stubPodEvictor) Delete(ctx context.Context, pod *corev1.Pod, grace int64) error { return nil }
+func (stubPodEvictor) EvictDryRun(ctx context.Context, pod *corev1.Pod) error         { return nil }
+func (stubPodEvictor) ListPendingPods(ctx context.Context) ([]corev1.Pod, error)      { return nil, nil }
+func (stubPodEvictor) ListPods(ctx context.Context) ([]corev1.Pod, error)             { return nil, nil }
+func (stubPodEvictor) WatchPodsOnNode(ctx context.Context, nodeName string) (watch.Interface, error) {
+	return watch.NewEmptyWatch(), nil
+}
+
+func nodeWithAllocatable(name string, cpu, mem string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(mem),
+			},
+		},
+	}
+}
+
+func podRequesting(cpu, mem string) corev1.Pod {
+	return corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse(mem),
+					},
+				},
+			}},
+		},
+	}
+}
+
+// TestRequestedFractionUsesPodRequests confirms requestedFraction is driven
+// by what's actually requested by pods scheduled on the node, not by
+// Capacity minus Allocatable -- that gap is fixed reserved overhead and
+// would misreport a genuinely empty node as full.
+func TestRequestedFractionUsesPodRequests(t *testing.T) {
+	node := nodeWithAllocatable("node-a", "10", "10Gi")
+	pods := stubPodEvictor{podsByNode: map[string][]corev1.Pod{
+		"node-a": {podRequesting("5", "1Gi")},
+	}}
+
+	got := requestedFraction(context.Background(), node, pods)
+	if want := 0.5; got != want {
+		t.Fatalf("requestedFraction = %v, want %v (5/10 CPU, the larger of the two fractions)", got, want)
+	}
+
+	empty := stubPodEvictor{podsByNode: map[string][]corev1.Pod{}}
+	if got := requestedFraction(context.Background(), node, empty); got != 0 {
+		t.Fatalf("requestedFraction of an empty node = %v, want 0", got)
+	}
+}
+
+// TestFragmentationScoreUsesPodRequests mirrors
+// TestRequestedFractionUsesPodRequests for fragmentationScore: a node
+// running hot on one resource and cold on the other, judged from actual pod
+// requests, should score high; a node requesting the same fraction of both
+// should score near zero regardless of how full it is overall.
+func TestFragmentationScoreUsesPodRequests(t *testing.T) {
+	node := nodeWithAllocatable("node-a", "10", "10Gi")
+
+	lopsided := stubPodEvictor{podsByNode: map[string][]corev1.Pod{
+		"node-a": {podRequesting("9", "1Gi")},
+	}}
+	if got := fragmentationScore(context.Background(), node, lopsided); got < 0.7 {
+		t.Fatalf("fragmentationScore of a CPU-hot, memory-cold node = %v, want a high score", got)
+	}
+
+	balanced := stubPodEvictor{podsByNode: map[string][]corev1.Pod{
+		"node-a": {podRequesting("5", "5Gi")},
+	}}
+	if got := fragmentationScore(context.Background(), node, balanced); got != 0 {
+		t.Fatalf("fragmentationScore of an evenly utilized node = %v, want 0", got)
+	}
+}
+
+func TestEmptiestFirstSelectorOrder(t *testing.T) {
+	full := nodeWithAllocatable("full", "10", "10Gi")
+	empty := nodeWithAllocatable("empty", "10", "10Gi")
+	pods := stubPodEvictor{podsByNode: map[string][]corev1.Pod{
+		"full": {podRequesting("9", "9Gi")},
+	}}
+
+	ordered := emptiestFirstSelector{}.Order(context.Background(), []corev1.Node{full, empty}, pods)
+	if ordered[0].Name != "empty" || ordered[1].Name != "full" {
+		t.Fatalf("emptiestFirstSelector.Order = %v, want [empty full]", []string{ordered[0].Name, ordered[1].Name})
+	}
+}
+
+func TestFragmentationSelectorOrder(t *testing.T) {
+	lopsided := nodeWithAllocatable("lopsided", "10", "10Gi")
+	balanced := nodeWithAllocatable("balanced", "10", "10Gi")
+	pods := stubPodEvictor{podsByNode: map[string][]corev1.Pod{
+		"lopsided": {podRequesting("9", "1Gi")},
+		"balanced": {podRequesting("5", "5Gi")},
+	}}
+
+	ordered := fragmentationSelector{}.Order(context.Background(), []corev1.Node{balanced, lopsided}, pods)
+	if ordered[0].Name != "lopsided" || ordered[1].Name != "balanced" {
+		t.Fatalf("fragmentationSelector.Order = %v, want [lopsided balanced]", []string{ordered[0].Name, ordered[1].Name})
+	}
+}
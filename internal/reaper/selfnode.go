@@ -0,0 +1,30 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ResolveSelfNodeName returns selfNodeName unchanged if it's already set
+// (typically from the NODE_NAME downward-API environment variable). If it's
+// empty but podName/podNamespace are set, it looks grim-reaper's own pod up
+// and returns the node it's running on, so self-preservation ordering
+// (deprioritizeSelf) still works on a manifest that only wired up
+// POD_NAME/POD_NAMESPACE. Returns "" if none of these are available.
+func ResolveSelfNodeName(clientset kubernetes.Interface, selfNodeName, podName, podNamespace string) (string, error) {
+	if selfNodeName != "" {
+		return selfNodeName, nil
+	}
+	if podName == "" || podNamespace == "" {
+		return "", nil
+	}
+
+	pod, err := clientset.CoreV1().Pods(podNamespace).Get(context.Background(), podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("resolving self node name from pod %s/%s: %w", podNamespace, podName, err)
+	}
+	return pod.Spec.NodeName, nil
+}
@@ -0,0 +1,261 @@
+package reaper
+
+import (
+	"context"
+	"log"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// PassoverReasonSchedulingInfeasible is recorded against nodes that were
+// otherwise selected for reaping, but simulation found at least one pod
+// with no feasible placement among the cluster's remaining nodes, once
+// nodeSelectors, required node affinity, tolerations, and topology spread
+// constraints are taken into account.
+const PassoverReasonSchedulingInfeasible = "scheduling-infeasible"
+
+// filterSchedulingFeasibility re-checks selected, in order, with a
+// lightweight bin-packing simulation: for every pod on a candidate node,
+// it looks for at least one remaining node -- allNodes minus whichever
+// candidates are still approved for removal at that point in the pass --
+// that the pod could actually be scheduled onto, given the pod's
+// nodeSelector, required node affinity, tolerations, and the remaining
+// nodes' current topology spread distribution. This catches placement
+// constraints Config.CapacityHeadroomCheck's raw CPU/memory sums can't
+// see, e.g. a GPU-only pod whose only other GPU node is also being
+// reaped. Pod affinity/anti-affinity isn't simulated -- doing so would
+// mean modeling every other pod's eventual placement too, not just the
+// cluster's static shape, which is out of scope for this check. A
+// candidate with an infeasible pod is passed over, and its own pods and
+// capacity remain available as a placement target for later candidates.
+// No-op if Config.SchedulerSimulationCheck is false.
+func (g *GrimReaper) filterSchedulingFeasibility(ctx context.Context, allNodes, selected []corev1.Node) []corev1.Node {
+	if !g.Config.SchedulerSimulationCheck {
+		return selected
+	}
+
+	podsByNode := map[string][]corev1.Pod{}
+	for i := range allNodes {
+		pods, err := g.Pods.ListPodsOnNode(ctx, allNodes[i].Name)
+		if err != nil {
+			log.Printf("scheduler simulation: listing pods on node %s: %v", allNodes[i].Name, err)
+			continue
+		}
+		podsByNode[allNodes[i].Name] = pods
+	}
+
+	removed := map[string]bool{}
+	for i := range selected {
+		removed[selected[i].Name] = true
+	}
+
+	var kept, passedOver []corev1.Node
+	for i := range selected {
+		node := &selected[i]
+		targets := remainingTargets(allNodes, removed, node.Name)
+
+		infeasible := false
+		for _, pod := range podsByNode[node.Name] {
+			if podHasFeasiblePlacement(&pod, targets, podsByNode) {
+				continue
+			}
+			log.Printf("node %s hosts pod %s/%s with no feasible placement elsewhere in the cluster; skipping", node.Name, pod.Namespace, pod.Name)
+			infeasible = true
+			break
+		}
+
+		if infeasible {
+			passedOver = append(passedOver, *node)
+			delete(removed, node.Name)
+			continue
+		}
+		kept = append(kept, *node)
+	}
+
+	g.recordPassovers(passedOver, PassoverReasonSchedulingInfeasible)
+	return kept
+}
+
+// remainingTargets returns the nodes from allNodes that aren't excludeSelf
+// and aren't still marked removed -- the candidate placement targets for a
+// pod being displaced from excludeSelf.
+func remainingTargets(allNodes []corev1.Node, removed map[string]bool, excludeSelf string) []*corev1.Node {
+	var targets []*corev1.Node
+	for i := range allNodes {
+		if allNodes[i].Name == excludeSelf || removed[allNodes[i].Name] {
+			continue
+		}
+		targets = append(targets, &allNodes[i])
+	}
+	return targets
+}
+
+// podHasFeasiblePlacement reports whether at least one of targets could
+// host pod, given its nodeSelector, required node affinity, tolerations,
+// and topology spread constraints.
+func podHasFeasiblePlacement(pod *corev1.Pod, targets []*corev1.Node, podsByNode map[string][]corev1.Pod) bool {
+	for _, target := range targets {
+		if !nodeSelectorMatches(pod, target) {
+			continue
+		}
+		if !nodeAffinityMatches(pod, target) {
+			continue
+		}
+		if !podTolerates(pod, target) {
+			continue
+		}
+		if !satisfiesTopologySpread(pod, target, targets, podsByNode) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// nodeSelectorMatches reports whether node's labels satisfy pod's plain
+// spec.nodeSelector.
+func nodeSelectorMatches(pod *corev1.Pod, node *corev1.Node) bool {
+	for k, v := range pod.Spec.NodeSelector {
+		if node.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeAffinityMatches reports whether node satisfies at least one term of
+// pod's required node affinity. Returns true if pod has none.
+func nodeAffinityMatches(pod *corev1.Pod, node *corev1.Node) bool {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil {
+		return true
+	}
+	required := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		return true
+	}
+	for _, term := range required.NodeSelectorTerms {
+		if nodeSelectorTermMatches(term, node.Labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeSelectorTermMatches reports whether every match expression in term
+// is satisfied by labels. matchFields (metadata-based selectors, rather
+// than labels) is uncommon enough in practice that it's treated as always
+// satisfied rather than modeled here.
+func nodeSelectorTermMatches(term corev1.NodeSelectorTerm, nodeLabels map[string]string) bool {
+	for _, expr := range term.MatchExpressions {
+		if !nodeSelectorRequirementMatches(expr, nodeLabels) {
+			return false
+		}
+	}
+	return true
+}
+
+func nodeSelectorRequirementMatches(req corev1.NodeSelectorRequirement, nodeLabels map[string]string) bool {
+	value, exists := nodeLabels[req.Key]
+	switch req.Operator {
+	case corev1.NodeSelectorOpIn:
+		return exists && stringSliceContains(req.Values, value)
+	case corev1.NodeSelectorOpNotIn:
+		return !exists || !stringSliceContains(req.Values, value)
+	case corev1.NodeSelectorOpExists:
+		return exists
+	case corev1.NodeSelectorOpDoesNotExist:
+		return !exists
+	default:
+		// Gt/Lt are rare enough in node affinity that treating them as
+		// satisfied beats blocking a harvest over them.
+		return true
+	}
+}
+
+func stringSliceContains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+// podTolerates reports whether pod tolerates every taint on node.
+func podTolerates(pod *corev1.Pod, node *corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		tolerated := false
+		for _, t := range pod.Spec.Tolerations {
+			if tolerationMatchesTaint(t, taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+func tolerationMatchesTaint(t corev1.Toleration, taint corev1.Taint) bool {
+	if t.Effect != "" && t.Effect != taint.Effect {
+		return false
+	}
+	if t.Key == "" {
+		return t.Operator == corev1.TolerationOpExists
+	}
+	if t.Key != taint.Key {
+		return false
+	}
+	if t.Operator == corev1.TolerationOpExists {
+		return true
+	}
+	return t.Value == taint.Value
+}
+
+// satisfiesTopologySpread reports whether placing pod onto target would
+// keep every DoNotSchedule topology spread constraint within its
+// maxSkew, counting pod's current matching siblings across targets (plus
+// target itself) as the simulated distribution.
+func satisfiesTopologySpread(pod *corev1.Pod, target *corev1.Node, targets []*corev1.Node, podsByNode map[string][]corev1.Pod) bool {
+	for _, c := range pod.Spec.TopologySpreadConstraints {
+		if c.WhenUnsatisfiable != corev1.DoNotSchedule {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(c.LabelSelector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+
+		counts := map[string]int{}
+		for _, t := range targets {
+			domain := t.Labels[c.TopologyKey]
+			for _, p := range podsByNode[t.Name] {
+				if selector.Matches(labels.Set(p.Labels)) {
+					counts[domain]++
+				}
+			}
+		}
+		counts[target.Labels[c.TopologyKey]]++
+
+		minCount, maxCount := -1, 0
+		for _, count := range counts {
+			if minCount == -1 || count < minCount {
+				minCount = count
+			}
+			if count > maxCount {
+				maxCount = count
+			}
+		}
+		if minCount == -1 {
+			minCount = 0
+		}
+		if int32(maxCount-minCount) > c.MaxSkew {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package reaper
+
+import (
+	"context"
+	"log"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	bytesPerMB = 1 << 20
+	bytesPerGB = 1 << 30
+)
+
+// nodeStatefulBytes sums the provisioned storage of every distinct
+// PVC-backed volume among pods, via PVCs. Returns 0 without error if PVCs
+// is unset, so stateful-data accounting is entirely opt-in.
+func (g *GrimReaper) nodeStatefulBytes(ctx context.Context, pods []corev1.Pod) int64 {
+	if g.PVCs == nil {
+		return 0
+	}
+
+	var total int64
+	seen := map[string]bool{}
+	for i := range pods {
+		for _, vol := range pods[i].Spec.Volumes {
+			if vol.PersistentVolumeClaim == nil {
+				continue
+			}
+			key := pods[i].Namespace + "/" + vol.PersistentVolumeClaim.ClaimName
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			claimBytes, err := g.PVCs.GetPVCStorageBytes(ctx, pods[i].Namespace, vol.PersistentVolumeClaim.ClaimName)
+			if err != nil {
+				log.Printf("estimating stateful data for PVC %s: %v", key, err)
+				continue
+			}
+			total += claimBytes
+		}
+	}
+	return total
+}
+
+// statefulReplicationDelay estimates how long the cluster's storage layer
+// needs to re-replicate or re-attach statefulBytes worth of data after the
+// node hosting it is deleted, at StatefulReplicationRateMBPerSec. Returns 0
+// if either is zero, disabling the extra pacing.
+func (g *GrimReaper) statefulReplicationDelay(statefulBytes int64) time.Duration {
+	if statefulBytes <= 0 || g.Config.StatefulReplicationRateMBPerSec <= 0 {
+		return 0
+	}
+	seconds := float64(statefulBytes) / (g.Config.StatefulReplicationRateMBPerSec * bytesPerMB)
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// maxStatefulBytesPerRun converts MaxStatefulGBPerRun into bytes. 0 means
+// no cap.
+func (g *GrimReaper) maxStatefulBytesPerRun() int64 {
+	if g.Config.MaxStatefulGBPerRun <= 0 {
+		return 0
+	}
+	return int64(g.Config.MaxStatefulGBPerRun * bytesPerGB)
+}
@@ -0,0 +1,36 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// pvcClient is the default PVCInterface implementation, backed by a real
+// (or fake) client-go clientset.
+type pvcClient struct {
+	clientset kubernetes.Interface
+}
+
+// NewPVCClient returns a PVCInterface backed by clientset.
+func NewPVCClient(clientset kubernetes.Interface) PVCInterface {
+	return &pvcClient{clientset: clientset}
+}
+
+func (p *pvcClient) GetPVCStorageBytes(ctx context.Context, namespace, name string) (int64, error) {
+	pvc, err := p.clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	if quantity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		return quantity.Value(), nil
+	}
+	if quantity, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		return quantity.Value(), nil
+	}
+	return 0, fmt.Errorf("PVC %s/%s has no storage capacity or request", namespace, name)
+}
@@ -0,0 +1,102 @@
+package reaper
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/BrianKopp/grim-reaper/internal/cloud"
+	"github.com/BrianKopp/grim-reaper/internal/metrics"
+)
+
+// terminationJob describes one instance whose node has already been
+// drained and deleted, and is now waiting to actually be terminated and
+// have that termination confirmed.
+type terminationJob struct {
+	nodeName   string
+	instanceID string
+	mode       cloud.DeletionMode
+	attempt    int
+}
+
+// AsyncTerminator runs cloud instance termination (TerminateInstance, then
+// WaitForTermination) on a background goroutine, decoupled from Harvest's
+// drain loop, so a slow or rate-limited cloud API doesn't stall draining
+// the next node. A failed termination is retried independently of
+// everything else in the queue, using the same BackoffPolicy eviction
+// retries use, up to MaxRetries attempts.
+type AsyncTerminator struct {
+	ctx        context.Context
+	cloud      cloud.Provider
+	backoff    BackoffPolicy
+	maxRetries int
+	timeout    time.Duration
+
+	jobs chan terminationJob
+}
+
+// NewAsyncTerminator starts an AsyncTerminator backed by provider,
+// retrying a failed termination with backoff up to maxRetries times (0
+// means unlimited) and waiting up to timeout for each termination attempt
+// to be confirmed. ctx is used for every cloud API call the terminator ever
+// makes, including ones for jobs enqueued long after the Harvest call that
+// created them; canceling it stops retrying and abandons whatever's still
+// in flight. It runs until ctx is done or the process exits; there's no
+// separate Stop.
+func NewAsyncTerminator(ctx context.Context, provider cloud.Provider, backoff BackoffPolicy, maxRetries int, timeout time.Duration) *AsyncTerminator {
+	t := &AsyncTerminator{
+		ctx:        ctx,
+		cloud:      provider,
+		backoff:    backoff,
+		maxRetries: maxRetries,
+		timeout:    timeout,
+		jobs:       make(chan terminationJob, 64),
+	}
+	go t.run()
+	return t
+}
+
+// Enqueue schedules instanceID, which backed nodeName, for asynchronous
+// termination in the given deletion mode (see cloud.DeletionMode) and
+// returns immediately without waiting for it to complete.
+func (t *AsyncTerminator) Enqueue(nodeName, instanceID string, mode cloud.DeletionMode) {
+	metrics.TerminationsPending.Inc()
+	t.jobs <- terminationJob{nodeName: nodeName, instanceID: instanceID, mode: mode}
+}
+
+// run drains the job queue forever, handling retries by re-enqueuing a job
+// after its backoff delay rather than blocking the worker goroutine.
+func (t *AsyncTerminator) run() {
+	for job := range t.jobs {
+		if t.ctx.Err() != nil {
+			return
+		}
+		t.attempt(job)
+	}
+}
+
+func (t *AsyncTerminator) attempt(job terminationJob) {
+	job.attempt++
+
+	err := t.cloud.TerminateInstance(t.ctx, job.instanceID, job.mode)
+	if err == nil {
+		err = t.cloud.WaitForTermination(t.ctx, job.instanceID, t.timeout)
+	}
+	if err == nil {
+		metrics.TerminationsPending.Dec()
+		log.Printf("confirmed termination of instance %s (node %s)", job.instanceID, job.nodeName)
+		return
+	}
+
+	if t.maxRetries > 0 && job.attempt >= t.maxRetries {
+		metrics.TerminationsPending.Dec()
+		metrics.TerminationsAbandonedTotal.Inc()
+		log.Printf("giving up terminating instance %s (node %s) after %d attempt(s): %v", job.instanceID, job.nodeName, job.attempt, err)
+		return
+	}
+
+	delay := t.backoff.NextDelay(job.attempt)
+	metrics.TerminationRetriesTotal.WithLabelValues(t.backoff.Name()).Inc()
+	log.Printf("terminating instance %s (node %s) failed (attempt %d): %v; retrying in %s", job.instanceID, job.nodeName, job.attempt, err, delay)
+	time.AfterFunc(delay, func() { t.jobs <- job })
+}
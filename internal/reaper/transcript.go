@@ -0,0 +1,47 @@
+package reaper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// transcriptEvent is a single timestamped step taken while harvesting a
+// node.
+type transcriptEvent struct {
+	Time   time.Time
+	Action string
+}
+
+// Transcript is the ordered list of actions grim-reaper took while
+// harvesting a single node. It's attached to failure notifications so a
+// responder can see exactly what happened before the failure without
+// digging through pod or API server logs.
+type Transcript struct {
+	NodeName string
+	events   []transcriptEvent
+}
+
+// NewTranscript starts a new, empty transcript for nodeName.
+func NewTranscript(nodeName string) *Transcript {
+	return &Transcript{NodeName: nodeName}
+}
+
+// Record appends a formatted, timestamped action to the transcript.
+func (t *Transcript) Record(format string, args ...interface{}) {
+	t.events = append(t.events, transcriptEvent{
+		Time:   time.Now(),
+		Action: fmt.Sprintf(format, args...),
+	})
+}
+
+// String renders the transcript as a human-readable, newline-separated
+// log, suitable for inlining into a notification message.
+func (t *Transcript) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "harvest transcript for node %s:\n", t.NodeName)
+	for _, e := range t.events {
+		fmt.Fprintf(&b, "  %s  %s\n", e.Time.Format(time.RFC3339), e.Action)
+	}
+	return b.String()
+}
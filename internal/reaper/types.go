@@ -0,0 +1,120 @@
+package reaper
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// NodeInterface abstracts the node operations GrimReaper needs, so selection
+// and harvesting can be exercised against a fake implementation in tests.
+// Every method takes a context.Context, threaded down from whatever
+// triggered the work (a Run, a signal-driven shutdown, a single CLI
+// subcommand), so a timeout or a lost leader election lease cancels
+// in-flight API calls instead of leaving them to run to completion.
+type NodeInterface interface {
+	// ListNodes returns every node matching selector ("" matches all nodes).
+	ListNodes(ctx context.Context, selector string) ([]corev1.Node, error)
+	Cordon(ctx context.Context, node *corev1.Node) error
+	Uncordon(ctx context.Context, node *corev1.Node) error
+	Delete(ctx context.Context, node *corev1.Node) error
+
+	// Annotate merges annotations into node's metadata. A nil value for a
+	// key removes that annotation. node.Annotations is updated in place to
+	// reflect the result.
+	Annotate(ctx context.Context, node *corev1.Node, annotations map[string]interface{}) error
+
+	// Taint adds (or replaces, if key is already present) a taint on node
+	// with the given value and effect. node.Spec.Taints is updated in place
+	// to reflect the result.
+	Taint(ctx context.Context, node *corev1.Node, key, value string, effect corev1.TaintEffect) error
+
+	// Untaint removes the taint identified by key from node, if present.
+	// node.Spec.Taints is updated in place to reflect the result.
+	Untaint(ctx context.Context, node *corev1.Node, key string) error
+
+	// SetCondition adds (or replaces, if its Type is already present) a
+	// condition in node's status. node.Status.Conditions is updated in
+	// place to reflect the result.
+	SetCondition(ctx context.Context, node *corev1.Node, condition corev1.NodeCondition) error
+}
+
+// NamespaceInterface abstracts looking up namespace objects, so the
+// tenant-tier eviction guard can be exercised against a fake in tests.
+type NamespaceInterface interface {
+	// GetNamespace returns the namespace named name.
+	GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error)
+}
+
+// PVCInterface abstracts looking up a PersistentVolumeClaim's provisioned
+// storage size, so stateful-data accounting can be exercised against a
+// fake in tests without depending on a specific storage backend.
+type PVCInterface interface {
+	// GetPVCStorageBytes returns the provisioned storage capacity of the
+	// PVC named name in namespace.
+	GetPVCStorageBytes(ctx context.Context, namespace, name string) (int64, error)
+}
+
+// EventReader abstracts reading Kubernetes Events for a single involved
+// object, so harvest-time event correlation can be exercised against a
+// fake in tests. It's read-only and scoped to one object at a time,
+// matching the narrow, single-purpose shape of GrimReaper's other
+// interfaces.
+type EventReader interface {
+	// ListEventsForObject returns every Event whose involvedObject is the
+	// one identified by namespace, name, and kind (e.g. "Node", "Pod").
+	ListEventsForObject(ctx context.Context, namespace, name, kind string) ([]corev1.Event, error)
+}
+
+// PDBInterface abstracts reading PodDisruptionBudgets, so the pre-drain
+// feasibility check can be exercised against a fake in tests.
+type PDBInterface interface {
+	// ListPDBs returns every PodDisruptionBudget in namespace.
+	ListPDBs(ctx context.Context, namespace string) ([]policyv1.PodDisruptionBudget, error)
+}
+
+// AlertGate abstracts checking which alerts are currently firing, so
+// post-harvest verification can detect an alert that started firing
+// because of a harvest without depending on a specific Prometheus/
+// Alertmanager deployment topology.
+type AlertGate interface {
+	// FiringAlerts returns the name of every alert currently firing.
+	FiringAlerts(ctx context.Context) ([]string, error)
+}
+
+// PodEvictor abstracts evicting the pods running on a node. NewPodEvictor
+// and its variants serve every method straight from the API server;
+// NewInformerPodEvictor wraps one of those to serve the read-only methods
+// (ListPodsOnNode, ListPendingPods, ListPods) from a local cache instead,
+// which node selection leans on heavily.
+type PodEvictor interface {
+	// ListPodsOnNode returns the pods scheduled onto nodeName.
+	ListPodsOnNode(ctx context.Context, nodeName string) ([]corev1.Pod, error)
+	Evict(ctx context.Context, pod *corev1.Pod) error
+
+	// Delete deletes pod directly, bypassing the eviction API, honoring
+	// gracePeriodSeconds. Used as the --force-delete-after-timeout fallback
+	// when Evict can't get a pod evicted within its retry budget.
+	Delete(ctx context.Context, pod *corev1.Pod, gracePeriodSeconds int64) error
+
+	// EvictDryRun asks the API server to validate (but not perform) an
+	// eviction, so PDB rejections can be observed without disrupting pod.
+	EvictDryRun(ctx context.Context, pod *corev1.Pod) error
+
+	// ListPendingPods returns every Pending pod in the cluster, used to
+	// gauge how much the scheduler is currently struggling.
+	ListPendingPods(ctx context.Context) ([]corev1.Pod, error)
+
+	// ListPods returns every pod in the cluster regardless of phase, used
+	// to check whether specific replacement pods have become Ready after
+	// a harvest.
+	ListPods(ctx context.Context) ([]corev1.Pod, error)
+
+	// WatchPodsOnNode returns a watch over pods with spec.nodeName=nodeName,
+	// shared by a single node's drain across every pod it evicts, so
+	// tracking termination on a dense node costs one watch connection
+	// instead of one Get per pod.
+	WatchPodsOnNode(ctx context.Context, nodeName string) (watch.Interface, error)
+}
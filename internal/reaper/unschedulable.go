@@ -0,0 +1,79 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// countUnschedulablePods returns how many of pods the scheduler has marked
+// Unschedulable via their PodScheduled condition.
+func countUnschedulablePods(pods []corev1.Pod) int {
+	count := 0
+	for i := range pods {
+		for _, cond := range pods[i].Status.Conditions {
+			if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse && cond.Reason == "Unschedulable" {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// checkUnschedulablePodsCircuitBreaker is config.Config.MaxUnschedulablePods:
+// a circuit breaker checked before a run starts (node nil) and again
+// before each subsequent node, since removing capacity while pods can't
+// already be scheduled only makes the pressure worse. While the cluster
+// has more Unschedulable pods than the threshold, it either aborts
+// outright (Config.UnschedulablePodsAbort) or pauses and rechecks --
+// mirroring awaitSchedulerCapacity -- every BackpressureCheckInterval,
+// until it clears or BackpressureTimeout elapses (0 waits indefinitely).
+// No-op if MaxUnschedulablePods is unset.
+func (g *GrimReaper) checkUnschedulablePodsCircuitBreaker(ctx context.Context, node *corev1.Node) error {
+	if g.Config.MaxUnschedulablePods <= 0 {
+		return nil
+	}
+
+	interval := g.Config.BackpressureCheckInterval
+	if interval <= 0 {
+		interval = DefaultBackpressureCheckInterval
+	}
+
+	start := time.Now()
+	for {
+		pending, err := g.Pods.ListPendingPods(ctx)
+		if err != nil {
+			log.Printf("unschedulable-pods circuit breaker: listing pending pods: %v", err)
+			return nil
+		}
+
+		count := countUnschedulablePods(pending)
+		if count <= g.Config.MaxUnschedulablePods {
+			return nil
+		}
+
+		if g.Config.UnschedulablePodsAbort {
+			return fmt.Errorf("%d pod(s) are Unschedulable, above max-unschedulable-pods=%d; aborting", count, g.Config.MaxUnschedulablePods)
+		}
+
+		if g.Config.BackpressureTimeout > 0 && time.Since(start) >= g.Config.BackpressureTimeout {
+			log.Printf("unschedulable-pods circuit breaker: gave up waiting for %d Unschedulable pod(s) to clear after %s, continuing anyway", count, g.Config.BackpressureTimeout)
+			return nil
+		}
+
+		nodeName := "the next node"
+		if node != nil {
+			nodeName = node.Name
+		}
+		log.Printf("unschedulable-pods circuit breaker: %d pod(s) Unschedulable, above max-unschedulable-pods=%d, pausing before harvesting %s", count, g.Config.MaxUnschedulablePods, nodeName)
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/BrianKopp/grim-reaper/internal/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// verifyPostHarvest checks a handful of cluster invariants right after
+// harvesting node, so a harvest that silently broke something is caught
+// before the next node starts instead of being discovered by an operator
+// later. preFiringAlerts is the set of alerts that were already firing
+// immediately before the harvest, used to tell "this harvest caused a new
+// alert" apart from "this alert was already firing for an unrelated
+// reason". Returns nil if config.Config.PostHarvestVerification is false.
+func (g *GrimReaper) verifyPostHarvest(ctx context.Context, node *corev1.Node, evictedPods []corev1.Pod, preFiringAlerts map[string]bool) *VerificationResult {
+	if !g.Config.PostHarvestVerification {
+		return nil
+	}
+
+	result := &VerificationResult{OK: true}
+	fail := func(format string, args ...interface{}) {
+		result.OK = false
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+	}
+
+	if remaining, err := g.Nodes.ListNodes(ctx, ""); err != nil {
+		log.Printf("post-harvest verification: listing nodes: %v", err)
+	} else if minNodes, err := config.ParseMinNodes(g.Config.MinNodes, len(remaining)); err != nil {
+		log.Printf("post-harvest verification: parsing min-nodes: %v", err)
+	} else if len(remaining) < minNodes {
+		fail("only %d node(s) remain, below min-nodes=%s (%d)", len(remaining), g.Config.MinNodes, minNodes)
+	}
+
+	if g.Config.VerifyPendingPodGrace > 0 {
+		displacedOwners := map[string]bool{}
+		for i := range evictedPods {
+			if key := podOwnerKey(&evictedPods[i]); key != "" {
+				displacedOwners[key] = true
+			}
+		}
+
+		if pending, err := g.Pods.ListPendingPods(ctx); err != nil {
+			log.Printf("post-harvest verification: listing pending pods: %v", err)
+		} else {
+			now := time.Now()
+			for i := range pending {
+				key := podOwnerKey(&pending[i])
+				if key == "" || !displacedOwners[key] {
+					continue
+				}
+				if age := now.Sub(pending[i].CreationTimestamp.Time); age > g.Config.VerifyPendingPodGrace {
+					fail("pod %s/%s, displaced from node %s, has been Pending for %s", pending[i].Namespace, pending[i].Name, node.Name, age.Round(time.Second))
+				}
+			}
+		}
+	}
+
+	if g.Alerts != nil {
+		firing, err := g.Alerts.FiringAlerts(ctx)
+		if err != nil {
+			log.Printf("post-harvest verification: listing firing alerts: %v", err)
+		} else {
+			for _, name := range firing {
+				if !preFiringAlerts[name] {
+					fail("new alert firing: %s", name)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// podOwnerKey identifies the controller pod belongs to (e.g. a ReplicaSet
+// or StatefulSet), so a Pending replacement pod can be matched back to the
+// workload a harvest displaced it from. Returns "" if pod has no
+// controller owner reference.
+func podOwnerKey(pod *corev1.Pod) string {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller {
+			return fmt.Sprintf("%s/%s/%s", pod.Namespace, ref.Kind, ref.Name)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WarmupReport summarizes the read-only inventory scan Warmup performs, so
+// an operator can see the cluster state a freshly deployed grim-reaper
+// found before it takes any destructive action on it.
+type WarmupReport struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+
+	TotalNodes           int      `json:"totalNodes"`
+	EligibleNodes        int      `json:"eligibleNodes"`
+	MarkedForDestruction []string `json:"markedForDestruction,omitempty"`
+	StaleMarkers         []string `json:"staleMarkers,omitempty"`
+	OrphanedCordons      []string `json:"orphanedCordons,omitempty"`
+}
+
+// Warmup performs a read-only inventory scan of every node matching
+// NodeSelector -- which ones grim-reaper already has marked for
+// destruction, which of those markers are stale (older than
+// StaleMarkerTTL), and which nodes are cordoned by something other than
+// grim-reaper (an "orphaned cordon", left over from manual intervention or
+// another tool) -- without cordoning, draining, or deleting anything.
+// Intended to run once at startup, before the first real run, so a freshly
+// deployed reaper's first action is never a surprise.
+//
+// Nodes are inspected one at a time, paced by WarmupScanQPS, rather than
+// all at once, so a large cluster's startup scan doesn't itself spike API
+// server load alongside everything else starting up in a fresh deploy.
+func (g *GrimReaper) Warmup(ctx context.Context) (*WarmupReport, error) {
+	nodes, err := g.Nodes.ListNodes(ctx, g.Config.NodeSelector)
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	report := &WarmupReport{GeneratedAt: time.Now(), TotalNodes: len(nodes)}
+	delay := warmupDelay(g.Config.WarmupScanQPS)
+
+	for i := range nodes {
+		if i > 0 && delay > 0 {
+			time.Sleep(delay)
+		}
+
+		node := &nodes[i]
+		switch {
+		case node.Annotations[ReapReasonAnnotation] != "":
+			report.MarkedForDestruction = append(report.MarkedForDestruction, node.Name)
+			if g.Config.StaleMarkerTTL > 0 && markerIsStale(node, g.Config.StaleMarkerTTL) {
+				report.StaleMarkers = append(report.StaleMarkers, node.Name)
+			}
+		case nodeOrphanCordoned(node):
+			report.OrphanedCordons = append(report.OrphanedCordons, node.Name)
+		default:
+			report.EligibleNodes++
+		}
+	}
+	return report, nil
+}
+
+// warmupDelay converts a QPS rate into the sleep between each node
+// inspected by Warmup. qps <= 0 disables pacing entirely.
+func warmupDelay(qps float64) time.Duration {
+	if qps <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / qps)
+}
@@ -0,0 +1,70 @@
+// Package schedule restricts reaping to approved maintenance windows using
+// a standard cron expression.
+package schedule
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Window reports whether now falls within an approved maintenance window
+// defined by a cron expression and a duration.
+type Window struct {
+	schedule cron.Schedule
+	duration time.Duration
+}
+
+// NewWindow parses cronExpr (standard 5-field cron) and returns a Window
+// whose maintenance period starts at each match and lasts duration.
+func NewWindow(cronExpr string, duration time.Duration) (*Window, error) {
+	schedule, err := cron.ParseStandard(cronExpr)
+	if err != nil {
+		return nil, err
+	}
+	return &Window{schedule: schedule, duration: duration}, nil
+}
+
+// maxLookback bounds how far InWindow will search into the past for the
+// schedule's most recent occurrence, guarding against a cron expression
+// (or a misconfigured one) that never matches at all.
+const maxLookback = 10 * 365 * 24 * time.Hour
+
+// InWindow reports whether now falls inside the most recent occurrence of
+// the schedule, extended by duration.
+func (w *Window) InWindow(now time.Time) bool {
+	// The most recent occurrence is the next occurrence from one full
+	// schedule period in the past; walking forward from there until we
+	// pass `now` finds it without needing a "previous" API from the cron
+	// library. The lookback starts at duration (the occurrence can't still
+	// be open any further back than that) and doubles until an occurrence
+	// turns up, so schedules with periods longer than a week -- monthly
+	// cron plus a multi-day window, say -- aren't missed by a fixed probe.
+	lookback := w.duration
+	if lookback <= 0 {
+		lookback = 24 * time.Hour
+	}
+
+	var last time.Time
+	for lookback <= maxLookback {
+		probe := now.Add(-lookback)
+		last = time.Time{}
+		for {
+			next := w.schedule.Next(probe)
+			if next.IsZero() || next.After(now) {
+				break
+			}
+			last = next
+			probe = next
+		}
+		if !last.IsZero() {
+			break
+		}
+		lookback *= 2
+	}
+
+	if last.IsZero() {
+		return false
+	}
+	return now.Before(last.Add(w.duration))
+}
@@ -0,0 +1,60 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInWindowWeeklySchedule(t *testing.T) {
+	w, err := NewWindow("0 0 * * 0", 2*time.Hour) // every Sunday at midnight
+	if err != nil {
+		t.Fatalf("NewWindow: unexpected error: %v", err)
+	}
+
+	sunday := time.Date(2026, time.January, 4, 0, 0, 0, 0, time.UTC)
+	if !w.InWindow(sunday.Add(time.Hour)) {
+		t.Fatal("InWindow: expected true one hour into a 2h window")
+	}
+	if w.InWindow(sunday.Add(3 * time.Hour)) {
+		t.Fatal("InWindow: expected false three hours into a 2h window")
+	}
+	if w.InWindow(sunday.Add(-time.Minute)) {
+		t.Fatal("InWindow: expected false before the window opens")
+	}
+}
+
+// TestInWindowPeriodLongerThanLookback is the regression case for a fixed
+// 7-day lookback: a monthly schedule with a multi-day window, evaluated
+// well into the window but more than a week after it opened, must still
+// report true.
+func TestInWindowPeriodLongerThanLookback(t *testing.T) {
+	w, err := NewWindow("0 0 1 * *", 15*24*time.Hour) // first of the month, open for 15 days
+	if err != nil {
+		t.Fatalf("NewWindow: unexpected error: %v", err)
+	}
+
+	opened := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tenDaysIn := opened.AddDate(0, 0, 10)
+	if !w.InWindow(tenDaysIn) {
+		t.Fatal("InWindow: expected true 10 days into a 15-day window opened by a monthly schedule")
+	}
+
+	afterClose := opened.AddDate(0, 0, 20)
+	if w.InWindow(afterClose) {
+		t.Fatal("InWindow: expected false 20 days into a 15-day window")
+	}
+}
+
+func TestInWindowNoPastOccurrence(t *testing.T) {
+	w, err := NewWindow("0 0 30 2 *", time.Hour) // February 30th, which never occurs
+	if err != nil {
+		t.Fatalf("NewWindow: unexpected error: %v", err)
+	}
+
+	// The schedule never matches, so no occurrence turns up however far
+	// back InWindow looks, up to maxLookback.
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if w.InWindow(now) {
+		t.Fatal("InWindow: expected false when no occurrence exists within the lookback bound")
+	}
+}
@@ -0,0 +1,299 @@
+// Package reapertest provides hand-written fakes for the interfaces
+// grim-reaper's core reaping logic depends on (node operations, pod
+// eviction, cloud termination, and notifications), so code embedding this
+// module can exercise its own wiring in tests without standing up a real
+// cluster or hand-rolling mocks for each interface.
+//
+// Every fake exposes its behavior as function fields (CordonFunc,
+// ListNodesFunc, and so on). Unset function fields are no-ops that return a
+// zero value, except FakePodEvictor.WatchPodsOnNode, which returns an empty
+// watch; FakeProvider and FakeNotifier still record each call. A test only
+// needs to fill in the behavior it cares about.
+package reapertest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/BrianKopp/grim-reaper/internal/cloud"
+	"github.com/BrianKopp/grim-reaper/internal/ledger"
+	"github.com/BrianKopp/grim-reaper/internal/notify"
+	"github.com/BrianKopp/grim-reaper/internal/reaper"
+)
+
+// FakeNodeInterface is a fake reaper.NodeInterface.
+type FakeNodeInterface struct {
+	ListNodesFunc    func(ctx context.Context, selector string) ([]corev1.Node, error)
+	CordonFunc       func(ctx context.Context, node *corev1.Node) error
+	UncordonFunc     func(ctx context.Context, node *corev1.Node) error
+	DeleteFunc       func(ctx context.Context, node *corev1.Node) error
+	AnnotateFunc     func(ctx context.Context, node *corev1.Node, annotations map[string]interface{}) error
+	TaintFunc        func(ctx context.Context, node *corev1.Node, key, value string, effect corev1.TaintEffect) error
+	UntaintFunc      func(ctx context.Context, node *corev1.Node, key string) error
+	SetConditionFunc func(ctx context.Context, node *corev1.Node, condition corev1.NodeCondition) error
+}
+
+var _ reaper.NodeInterface = &FakeNodeInterface{}
+
+func (f *FakeNodeInterface) ListNodes(ctx context.Context, selector string) ([]corev1.Node, error) {
+	if f.ListNodesFunc == nil {
+		return nil, nil
+	}
+	return f.ListNodesFunc(ctx, selector)
+}
+
+func (f *FakeNodeInterface) Cordon(ctx context.Context, node *corev1.Node) error {
+	if f.CordonFunc == nil {
+		return nil
+	}
+	return f.CordonFunc(ctx, node)
+}
+
+func (f *FakeNodeInterface) Uncordon(ctx context.Context, node *corev1.Node) error {
+	if f.UncordonFunc == nil {
+		return nil
+	}
+	return f.UncordonFunc(ctx, node)
+}
+
+func (f *FakeNodeInterface) Delete(ctx context.Context, node *corev1.Node) error {
+	if f.DeleteFunc == nil {
+		return nil
+	}
+	return f.DeleteFunc(ctx, node)
+}
+
+func (f *FakeNodeInterface) Annotate(ctx context.Context, node *corev1.Node, annotations map[string]interface{}) error {
+	if f.AnnotateFunc == nil {
+		return nil
+	}
+	return f.AnnotateFunc(ctx, node, annotations)
+}
+
+func (f *FakeNodeInterface) Taint(ctx context.Context, node *corev1.Node, key, value string, effect corev1.TaintEffect) error {
+	if f.TaintFunc == nil {
+		return nil
+	}
+	return f.TaintFunc(ctx, node, key, value, effect)
+}
+
+func (f *FakeNodeInterface) Untaint(ctx context.Context, node *corev1.Node, key string) error {
+	if f.UntaintFunc == nil {
+		return nil
+	}
+	return f.UntaintFunc(ctx, node, key)
+}
+
+func (f *FakeNodeInterface) SetCondition(ctx context.Context, node *corev1.Node, condition corev1.NodeCondition) error {
+	if f.SetConditionFunc == nil {
+		return nil
+	}
+	return f.SetConditionFunc(ctx, node, condition)
+}
+
+// FakeNamespaceInterface is a fake reaper.NamespaceInterface.
+type FakeNamespaceInterface struct {
+	GetNamespaceFunc func(ctx context.Context, name string) (*corev1.Namespace, error)
+}
+
+var _ reaper.NamespaceInterface = &FakeNamespaceInterface{}
+
+func (f *FakeNamespaceInterface) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	if f.GetNamespaceFunc == nil {
+		return nil, nil
+	}
+	return f.GetNamespaceFunc(ctx, name)
+}
+
+// FakePVCInterface is a fake reaper.PVCInterface.
+type FakePVCInterface struct {
+	GetPVCStorageBytesFunc func(ctx context.Context, namespace, name string) (int64, error)
+}
+
+var _ reaper.PVCInterface = &FakePVCInterface{}
+
+func (f *FakePVCInterface) GetPVCStorageBytes(ctx context.Context, namespace, name string) (int64, error) {
+	if f.GetPVCStorageBytesFunc == nil {
+		return 0, nil
+	}
+	return f.GetPVCStorageBytesFunc(ctx, namespace, name)
+}
+
+// FakeEventReader is a fake reaper.EventReader.
+type FakeEventReader struct {
+	ListEventsForObjectFunc func(ctx context.Context, namespace, name, kind string) ([]corev1.Event, error)
+}
+
+var _ reaper.EventReader = &FakeEventReader{}
+
+func (f *FakeEventReader) ListEventsForObject(ctx context.Context, namespace, name, kind string) ([]corev1.Event, error) {
+	if f.ListEventsForObjectFunc == nil {
+		return nil, nil
+	}
+	return f.ListEventsForObjectFunc(ctx, namespace, name, kind)
+}
+
+// FakePDBInterface is a fake reaper.PDBInterface.
+type FakePDBInterface struct {
+	ListPDBsFunc func(ctx context.Context, namespace string) ([]policyv1.PodDisruptionBudget, error)
+}
+
+var _ reaper.PDBInterface = &FakePDBInterface{}
+
+func (f *FakePDBInterface) ListPDBs(ctx context.Context, namespace string) ([]policyv1.PodDisruptionBudget, error) {
+	if f.ListPDBsFunc == nil {
+		return nil, nil
+	}
+	return f.ListPDBsFunc(ctx, namespace)
+}
+
+// FakePodEvictor is a fake reaper.PodEvictor.
+type FakePodEvictor struct {
+	ListPodsOnNodeFunc  func(ctx context.Context, nodeName string) ([]corev1.Pod, error)
+	EvictFunc           func(ctx context.Context, pod *corev1.Pod) error
+	DeleteFunc          func(ctx context.Context, pod *corev1.Pod, gracePeriodSeconds int64) error
+	EvictDryRunFunc     func(ctx context.Context, pod *corev1.Pod) error
+	ListPendingPodsFunc func(ctx context.Context) ([]corev1.Pod, error)
+	ListPodsFunc        func(ctx context.Context) ([]corev1.Pod, error)
+	WatchPodsOnNodeFunc func(ctx context.Context, nodeName string) (watch.Interface, error)
+}
+
+var _ reaper.PodEvictor = &FakePodEvictor{}
+
+func (f *FakePodEvictor) ListPodsOnNode(ctx context.Context, nodeName string) ([]corev1.Pod, error) {
+	if f.ListPodsOnNodeFunc == nil {
+		return nil, nil
+	}
+	return f.ListPodsOnNodeFunc(ctx, nodeName)
+}
+
+func (f *FakePodEvictor) Evict(ctx context.Context, pod *corev1.Pod) error {
+	if f.EvictFunc == nil {
+		return nil
+	}
+	return f.EvictFunc(ctx, pod)
+}
+
+func (f *FakePodEvictor) Delete(ctx context.Context, pod *corev1.Pod, gracePeriodSeconds int64) error {
+	if f.DeleteFunc == nil {
+		return nil
+	}
+	return f.DeleteFunc(ctx, pod, gracePeriodSeconds)
+}
+
+func (f *FakePodEvictor) EvictDryRun(ctx context.Context, pod *corev1.Pod) error {
+	if f.EvictDryRunFunc == nil {
+		return nil
+	}
+	return f.EvictDryRunFunc(ctx, pod)
+}
+
+func (f *FakePodEvictor) ListPendingPods(ctx context.Context) ([]corev1.Pod, error) {
+	if f.ListPendingPodsFunc == nil {
+		return nil, nil
+	}
+	return f.ListPendingPodsFunc(ctx)
+}
+
+func (f *FakePodEvictor) ListPods(ctx context.Context) ([]corev1.Pod, error) {
+	if f.ListPodsFunc == nil {
+		return nil, nil
+	}
+	return f.ListPodsFunc(ctx)
+}
+
+func (f *FakePodEvictor) WatchPodsOnNode(ctx context.Context, nodeName string) (watch.Interface, error) {
+	if f.WatchPodsOnNodeFunc == nil {
+		return watch.NewEmptyWatch(), nil
+	}
+	return f.WatchPodsOnNodeFunc(ctx, nodeName)
+}
+
+// FakeProvider is a fake cloud.Provider that records every call it
+// receives, for assertions, in addition to invoking any configured
+// function fields.
+type FakeProvider struct {
+	TerminateInstanceFunc  func(ctx context.Context, instanceID string, mode cloud.DeletionMode) error
+	WaitForTerminationFunc func(ctx context.Context, instanceID string, timeout time.Duration) error
+	ProtectFromScaleInFunc func(ctx context.Context, instanceID string, protected bool) error
+
+	TerminatedInstanceIDs []string
+}
+
+var _ cloud.Provider = &FakeProvider{}
+
+func (f *FakeProvider) TerminateInstance(ctx context.Context, instanceID string, mode cloud.DeletionMode) error {
+	f.TerminatedInstanceIDs = append(f.TerminatedInstanceIDs, instanceID)
+	if f.TerminateInstanceFunc == nil {
+		return nil
+	}
+	return f.TerminateInstanceFunc(ctx, instanceID, mode)
+}
+
+func (f *FakeProvider) WaitForTermination(ctx context.Context, instanceID string, timeout time.Duration) error {
+	if f.WaitForTerminationFunc == nil {
+		return nil
+	}
+	return f.WaitForTerminationFunc(ctx, instanceID, timeout)
+}
+
+func (f *FakeProvider) ProtectFromScaleIn(ctx context.Context, instanceID string, protected bool) error {
+	if f.ProtectFromScaleInFunc == nil {
+		return nil
+	}
+	return f.ProtectFromScaleInFunc(ctx, instanceID, protected)
+}
+
+// FakeNotifier is a fake notify.Notifier that records every message it
+// receives.
+type FakeNotifier struct {
+	NotifyFunc func(message string) error
+	Messages   []string
+}
+
+var _ notify.Notifier = &FakeNotifier{}
+
+func (f *FakeNotifier) Notify(message string) error {
+	f.Messages = append(f.Messages, message)
+	if f.NotifyFunc == nil {
+		return nil
+	}
+	return f.NotifyFunc(message)
+}
+
+// FakeDisruptionReader is a fake ledger.DisruptionReader.
+type FakeDisruptionReader struct {
+	CountRecentFunc func(window time.Duration) (int, error)
+}
+
+var _ ledger.DisruptionReader = &FakeDisruptionReader{}
+
+func (f *FakeDisruptionReader) CountRecent(window time.Duration) (int, error) {
+	if f.CountRecentFunc == nil {
+		return 0, nil
+	}
+	return f.CountRecentFunc(window)
+}
+
+// FakeAlertGate is a fake reaper.AlertGate.
+type FakeAlertGate struct {
+	FiringAlertsFunc func(ctx context.Context) ([]string, error)
+}
+
+var _ reaper.AlertGate = &FakeAlertGate{}
+
+func (f *FakeAlertGate) FiringAlerts(ctx context.Context) ([]string, error) {
+	if f.FiringAlertsFunc == nil {
+		return nil, nil
+	}
+	return f.FiringAlertsFunc(ctx)
+}
+
+// ErrNotFound is a convenience sentinel fakes can return from a lookup
+// function to simulate a missing node or pod.
+var ErrNotFound = fmt.Errorf("reapertest: not found")
@@ -0,0 +1,83 @@
+package reapertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/BrianKopp/grim-reaper/internal/cloud"
+)
+
+// TestFakesZeroValue confirms every fake's unset function fields are
+// no-ops returning a zero value, so a test only has to fill in the
+// behavior it cares about, per the package doc comment's contract.
+func TestFakesZeroValue(t *testing.T) {
+	ctx := context.Background()
+
+	nodes := &FakeNodeInterface{}
+	if list, err := nodes.ListNodes(ctx, ""); list != nil || err != nil {
+		t.Fatalf("FakeNodeInterface.ListNodes zero value = (%v, %v), want (nil, nil)", list, err)
+	}
+	if err := nodes.Cordon(ctx, &corev1.Node{}); err != nil {
+		t.Fatalf("FakeNodeInterface.Cordon zero value = %v, want nil", err)
+	}
+
+	pods := &FakePodEvictor{}
+	if list, err := pods.ListPodsOnNode(ctx, "node"); list != nil || err != nil {
+		t.Fatalf("FakePodEvictor.ListPodsOnNode zero value = (%v, %v), want (nil, nil)", list, err)
+	}
+	w, err := pods.WatchPodsOnNode(ctx, "node")
+	if err != nil || w == nil {
+		t.Fatalf("FakePodEvictor.WatchPodsOnNode zero value = (%v, %v), want a non-nil empty watch and nil error", w, err)
+	}
+
+	notifier := &FakeNotifier{}
+	if err := notifier.Notify("hello"); err != nil {
+		t.Fatalf("FakeNotifier.Notify zero value = %v, want nil", err)
+	}
+}
+
+// TestFakesOverride confirms a configured function field is actually
+// invoked in place of the default no-op, and that fakes which record
+// calls (FakeProvider, FakeNotifier) do so.
+func TestFakesOverride(t *testing.T) {
+	ctx := context.Background()
+	wantErr := errors.New("boom")
+
+	nodes := &FakeNodeInterface{
+		CordonFunc: func(ctx context.Context, node *corev1.Node) error { return wantErr },
+	}
+	if err := nodes.Cordon(ctx, &corev1.Node{}); err != wantErr {
+		t.Fatalf("FakeNodeInterface.Cordon override = %v, want %v", err, wantErr)
+	}
+
+	provider := &FakeProvider{}
+	if err := provider.TerminateInstance(ctx, "instance-a", cloud.DeleteModeShrink); err != nil {
+		t.Fatalf("FakeProvider.TerminateInstance: unexpected error: %v", err)
+	}
+	if err := provider.TerminateInstance(ctx, "instance-b", cloud.DeleteModeShrink); err != nil {
+		t.Fatalf("FakeProvider.TerminateInstance: unexpected error: %v", err)
+	}
+	if want := []string{"instance-a", "instance-b"}; len(provider.TerminatedInstanceIDs) != len(want) ||
+		provider.TerminatedInstanceIDs[0] != want[0] || provider.TerminatedInstanceIDs[1] != want[1] {
+		t.Fatalf("FakeProvider.TerminatedInstanceIDs = %v, want %v", provider.TerminatedInstanceIDs, want)
+	}
+
+	notifier := &FakeNotifier{}
+	if err := notifier.Notify("node drained"); err != nil {
+		t.Fatalf("FakeNotifier.Notify: unexpected error: %v", err)
+	}
+	if len(notifier.Messages) != 1 || notifier.Messages[0] != "node drained" {
+		t.Fatalf("FakeNotifier.Messages = %v, want [%q]", notifier.Messages, "node drained")
+	}
+
+	disruption := &FakeDisruptionReader{
+		CountRecentFunc: func(window time.Duration) (int, error) { return 3, nil },
+	}
+	if count, err := disruption.CountRecent(time.Hour); err != nil || count != 3 {
+		t.Fatalf("FakeDisruptionReader.CountRecent override = (%d, %v), want (3, nil)", count, err)
+	}
+}